@@ -1,37 +1,130 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/Illuminateee/task-tracker.git/config"
 	"github.com/Illuminateee/task-tracker.git/delivery/controller"
 )
 
-func main() {
-	// Get the data file path (tasks.json in current directory)
-	dataFilePath := getDataFilePath()
+// Process exit codes, one per error class reported by controller.ErrorCode
+const (
+	exitInternal     = 1
+	exitUsage        = 2
+	exitInvalidInput = 3
+	exitNotFound     = 4
+	exitConflict     = 5
+	exitStorage      = 6
+)
 
-	// Create CLI controller
-	cliController := controller.NewCLIController(dataFilePath)
+// jsonError is the stderr payload written in --json-errors mode
+type jsonError struct {
+	Error struct {
+		Code     string `json:"code"`
+		Message  string `json:"message"`
+		ExitCode int    `json:"exit_code"`
+	} `json:"error"`
+}
 
-	// Get command line arguments (skip program name)
+func main() {
+	// Parse global flags that precede the command
+	flags := flag.NewFlagSet("task-tracker", flag.ContinueOnError)
+	configPath := flags.String("config", os.Getenv("TASK_TRACKER_CONFIG"), "path to the config file")
+	profile := flags.String("profile", os.Getenv("TASK_TRACKER_PROFILE"), "config profile to use")
+	dataPath := flags.String("data", "", "path to the tasks file")
+	jsonErrors := flags.Bool("json-errors", false, "report errors as JSON on stderr")
+	// Parsing stops at the command; -h and --help before it show the command help
+	flags.Usage = func() {}
 	args := os.Args[1:]
+	if err := flags.Parse(args); errors.Is(err, flag.ErrHelp) {
+		args = []string{"help"}
+	} else if err != nil {
+		fmt.Fprintln(os.Stderr, "Global flags:")
+		flags.PrintDefaults()
+		os.Exit(exitUsage)
+	} else {
+		args = flags.Args()
+	}
+
+	cfg, err := config.Load(*configPath, *profile)
+	if err != nil {
+		exit(&controller.UsageError{Message: err.Error()}, *jsonErrors)
+	}
+	cfg.DataFile = getDataFilePath(cfg, *dataPath)
+
+	// Create CLI controller
+	cliController := controller.NewCLIController(cfg)
 
 	// Handle the command
-	if err := cliController.HandleCommand(args); err != nil {
+	err = cliController.HandleCommand(args)
+	cliController.Close()
+	if err != nil {
+		exit(err, *jsonErrors)
+	}
+}
+
+// exit reports err on stderr and terminates with the exit code for its class
+func exit(err error, asJSON bool) {
+	// A plugin reports its own errors; pass its exit status through
+	var pluginErr *controller.PluginExitError
+	if errors.As(err, &pluginErr) {
+		os.Exit(pluginErr.Code)
+	}
+
+	code := controller.ErrorCode(err)
+	exitCode := exitCodeFor(code)
+
+	if asJSON {
+		var payload jsonError
+		payload.Error.Code = code
+		payload.Error.Message = err.Error()
+		payload.Error.ExitCode = exitCode
+		json.NewEncoder(os.Stderr).Encode(payload)
+	} else {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	}
+	os.Exit(exitCode)
+}
+
+// exitCodeFor maps an error code to a process exit code
+func exitCodeFor(code string) int {
+	switch code {
+	case controller.ErrorCodeUsage:
+		return exitUsage
+	case controller.ErrorCodeInvalidInput:
+		return exitInvalidInput
+	case controller.ErrorCodeNotFound:
+		return exitNotFound
+	case controller.ErrorCodeConflict:
+		return exitConflict
+	case controller.ErrorCodeStorage:
+		return exitStorage
+	default:
+		return exitInternal
 	}
 }
 
-// getDataFilePath returns the path to the tasks.json file
-func getDataFilePath() string {
-	// Try to get from environment variable first
+// getDataFilePath returns the path to the tasks file, preferring the --data
+// flag, then TASK_TRACKER_DATA, then the config file
+func getDataFilePath(cfg *config.Config, flagPath string) string {
+	if flagPath != "" {
+		return flagPath
+	}
+
+	// Try to get from environment variable next
 	if dataPath := os.Getenv("TASK_TRACKER_DATA"); dataPath != "" {
 		return dataPath
 	}
 
+	if cfg.DataFile != "" {
+		return cfg.DataFile
+	}
+
 	// Get current working directory
 	cwd, err := os.Getwd()
 	if err != nil {
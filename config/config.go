@@ -0,0 +1,381 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Color modes for terminal output
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// Config holds user settings loaded from the config file
+type Config struct {
+	// Path is the config file that was loaded, empty when none was found
+	Path string
+	// Profile is the name of the applied profile, empty for none
+	Profile string
+
+	DataFile      string
+	DefaultFilter string
+	DateFormat    string
+	Color         string
+	// StorageFormat is the format used when creating a new tasks file
+	StorageFormat string
+	// KeyFile holds the key material for an encrypted tasks file
+	KeyFile string
+	// SyncURL is the sync server used by the sync command
+	SyncURL string
+	// SyncToken is sent to the sync server as a bearer token
+	SyncToken string
+	// APIToken, when set, must be sent to serve as a bearer token
+	APIToken string
+	// RemindBefore is how long before a task is due the watch command reminds about it
+	RemindBefore time.Duration
+	// RemindLog is the file reminders are logged to, empty for stdout
+	RemindLog string
+	// RemindCommand is run for every reminder, empty for none
+	RemindCommand string
+	// RemindWebhook receives every reminder as a JSON POST, empty for none
+	RemindWebhook string
+	// HooksDir holds the scripts run on task events
+	HooksDir string
+	// Webhooks receive every task change as a signed JSON POST
+	Webhooks []string
+	// WebhookSecret is the HMAC key webhook payloads are signed with
+	WebhookSecret string
+	// PluginsDir is searched for task-tracker-<name> commands before PATH
+	PluginsDir string
+	// ConfirmThreshold is the number of tasks a bulk command may change without confirmation
+	ConfirmThreshold int
+
+	// Aliases maps an alias name to the command line it expands to
+	Aliases map[string]string
+}
+
+// section is a named group of settings in the config file
+type section struct {
+	name    string
+	entries []entry
+}
+
+// entry is a single key = value line
+type entry struct {
+	key   string
+	value string
+	line  int
+}
+
+// Default returns the built-in configuration
+func Default() *Config {
+	return &Config{
+		DefaultFilter:    "all",
+		DateFormat:       time.RFC3339,
+		Color:            ColorAuto,
+		StorageFormat:    "json",
+		RemindBefore:     time.Hour,
+		HooksDir:         defaultConfigDir("hooks"),
+		PluginsDir:       defaultConfigDir("plugins"),
+		ConfirmThreshold: 5,
+		Aliases:          make(map[string]string),
+	}
+}
+
+// DefaultPath returns the config file location in the user config directory
+func DefaultPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(configDir, "task-tracker", "config"), nil
+}
+
+// defaultConfigDir returns the named directory next to the default config file
+func defaultConfigDir(name string) string {
+	path, err := DefaultPath()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(path), name)
+}
+
+// Load reads the config file at path and applies the named profile; an empty
+// path uses DefaultPath and tolerates the file not existing
+func Load(path, profile string) (*Config, error) {
+	cfg := Default()
+
+	explicit := path != ""
+	if !explicit {
+		defaultPath, err := DefaultPath()
+		if err != nil {
+			return applyProfile(cfg, nil, profile)
+		}
+		path = defaultPath
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !explicit {
+			return applyProfile(cfg, nil, profile)
+		}
+		return nil, fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer file.Close()
+
+	sections, err := parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	cfg.Path = path
+
+	// Validate every section, not just the selected profile
+	for _, s := range sections[1:] {
+		if s.name == "alias" {
+			if err := cfg.setAliases(s.entries); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			continue
+		}
+		if !strings.HasPrefix(s.name, "profile ") {
+			return nil, fmt.Errorf("%s: unknown section [%s]", path, s.name)
+		}
+		scratch := Default()
+		for _, e := range s.entries {
+			if err := scratch.set(e); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+		}
+	}
+
+	// Top-level settings apply first; a top-level "profile" selects the default profile
+	for _, e := range sections[0].entries {
+		if e.key == "profile" {
+			if profile == "" {
+				profile = unquote(e.value)
+			}
+			continue
+		}
+		if err := cfg.set(e); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	cfg, err = applyProfile(cfg, sections[1:], profile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if len(cfg.Webhooks) > 0 && cfg.WebhookSecret == "" {
+		return nil, fmt.Errorf("%s: webhooks requires webhook_secret to sign the payloads", path)
+	}
+
+	cfg.DataFile = cfg.resolvePath(cfg.DataFile)
+	cfg.KeyFile = cfg.resolvePath(cfg.KeyFile)
+	cfg.RemindLog = cfg.resolvePath(cfg.RemindLog)
+	cfg.HooksDir = cfg.resolvePath(cfg.HooksDir)
+	cfg.PluginsDir = cfg.resolvePath(cfg.PluginsDir)
+	return cfg, nil
+}
+
+// applyProfile layers the settings of the named profile over cfg
+func applyProfile(cfg *Config, sections []section, profile string) (*Config, error) {
+	if profile == "" {
+		return cfg, nil
+	}
+
+	for _, s := range sections {
+		if s.name != "profile "+profile {
+			continue
+		}
+		for _, e := range s.entries {
+			if err := cfg.set(e); err != nil {
+				return nil, err
+			}
+		}
+		cfg.Profile = profile
+		return cfg, nil
+	}
+	return nil, fmt.Errorf("profile %q not found", profile)
+}
+
+// set applies a single setting, validating its value
+func (c *Config) set(e entry) error {
+	value := unquote(e.value)
+	switch e.key {
+	case "data":
+		c.DataFile = value
+	case "default_filter":
+		switch value {
+		case "all", "done", "todo", "in-progress", "pending":
+			c.DefaultFilter = value
+		default:
+			return fmt.Errorf("line %d: invalid default_filter %q. Valid filters: all, done, todo, in-progress, pending", e.line, value)
+		}
+	case "date_format":
+		c.DateFormat = dateLayout(value)
+	case "color":
+		switch value {
+		case ColorAuto, ColorAlways, ColorNever:
+			c.Color = value
+		default:
+			return fmt.Errorf("line %d: invalid color %q. Valid values: auto, always, never", e.line, value)
+		}
+	case "key_file":
+		c.KeyFile = value
+	case "sync_url":
+		c.SyncURL = value
+	case "sync_token":
+		c.SyncToken = value
+	case "api_token":
+		c.APIToken = value
+	case "webhooks":
+		c.Webhooks = nil
+		for _, raw := range strings.Split(value, ",") {
+			raw = strings.TrimSpace(raw)
+			if raw == "" {
+				continue
+			}
+			if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("line %d: invalid webhook URL %q. Expected an http or https URL", e.line, raw)
+			}
+			c.Webhooks = append(c.Webhooks, raw)
+		}
+	case "webhook_secret":
+		c.WebhookSecret = value
+	case "plugins_dir":
+		c.PluginsDir = value
+	case "hooks_dir":
+		c.HooksDir = value
+	case "remind_before":
+		before, err := time.ParseDuration(value)
+		if err != nil || before < 0 {
+			return fmt.Errorf("line %d: invalid remind_before %q. Expected a duration such as 30m or 2h", e.line, value)
+		}
+		c.RemindBefore = before
+	case "remind_log":
+		c.RemindLog = value
+	case "remind_command":
+		c.RemindCommand = value
+	case "remind_webhook":
+		c.RemindWebhook = value
+	case "storage_format":
+		switch value {
+		case "json", "binary":
+			c.StorageFormat = value
+		default:
+			return fmt.Errorf("line %d: invalid storage_format %q. Valid formats: json, binary", e.line, value)
+		}
+	case "confirm_threshold":
+		threshold, err := strconv.Atoi(value)
+		if err != nil || threshold < 0 {
+			return fmt.Errorf("line %d: invalid confirm_threshold %q. Expected a non-negative number", e.line, value)
+		}
+		c.ConfirmThreshold = threshold
+	default:
+		return fmt.Errorf("line %d: unknown setting %q", e.line, e.key)
+	}
+	return nil
+}
+
+// setAliases records the entries of an [alias] section
+func (c *Config) setAliases(entries []entry) error {
+	for _, e := range entries {
+		name := strings.ToLower(e.key)
+		if name == "" || strings.ContainsAny(name, " \t") {
+			return fmt.Errorf("line %d: invalid alias name %q", e.line, e.key)
+		}
+		if strings.TrimSpace(e.value) == "" {
+			return fmt.Errorf("line %d: alias %q has an empty expansion", e.line, e.key)
+		}
+		c.Aliases[name] = e.value
+	}
+	return nil
+}
+
+// resolvePath expands ~ and makes relative paths relative to the config file
+func (c *Config) resolvePath(path string) string {
+	if path == "" {
+		return ""
+	}
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+	if !filepath.IsAbs(path) && c.Path != "" {
+		path = filepath.Join(filepath.Dir(c.Path), path)
+	}
+	return path
+}
+
+// unquote strips one pair of surrounding double quotes
+func unquote(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// dateLayout maps named date formats to Go time layouts
+func dateLayout(format string) string {
+	switch strings.ToLower(format) {
+	case "rfc3339":
+		return time.RFC3339
+	case "date":
+		return "2006-01-02"
+	case "datetime":
+		return "2006-01-02 15:04"
+	default:
+		return format
+	}
+}
+
+// parse reads an INI-style file; the first section holds top-level settings
+func parse(file *os.File) ([]section, error) {
+	sections := []section{{}}
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unterminated section header", lineNumber)
+			}
+			name := strings.Join(strings.Fields(line[1:len(line)-1]), " ")
+			sections = append(sections, section{name: name})
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNumber)
+		}
+		current := &sections[len(sections)-1]
+		current.entries = append(current.entries, entry{
+			key:   strings.TrimSpace(key),
+			value: strings.TrimSpace(value),
+			line:  lineNumber,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return sections, nil
+}
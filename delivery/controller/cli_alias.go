@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// runAlias expands an alias with args and runs each && separated step in order,
+// stopping at the first failure
+func (c *CLIController) runAlias(name string, args []string, expanding []string) error {
+	steps, err := expandAlias(c.config.Aliases[name], args)
+	if err != nil {
+		return fmt.Errorf("alias %s: %w", name, err)
+	}
+
+	for _, step := range steps {
+		if err := c.dispatch(step, expanding); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandAlias splits an alias into steps at unquoted && words and substitutes
+// $1, $2, ... and $@; when no placeholders are used the arguments are appended
+// to the last step
+func expandAlias(expansion string, args []string) ([][]string, error) {
+	words, err := splitWords(expansion)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := [][]string{nil}
+	usesPlaceholders := false
+	for _, word := range words {
+		last := len(steps) - 1
+		if word.text == "&&" && !word.quoted {
+			if len(steps[last]) == 0 {
+				return nil, usageErrorf("empty step in %q", expansion)
+			}
+			steps = append(steps, nil)
+			continue
+		}
+		if word.text == "$@" && !word.quoted {
+			usesPlaceholders = true
+			steps[last] = append(steps[last], args...)
+			continue
+		}
+
+		substituted, used, err := substituteArgs(word.text, args)
+		if err != nil {
+			return nil, err
+		}
+		usesPlaceholders = usesPlaceholders || used
+		steps[last] = append(steps[last], substituted)
+	}
+	if len(steps[len(steps)-1]) == 0 {
+		return nil, usageErrorf("empty step in %q", expansion)
+	}
+
+	if !usesPlaceholders {
+		last := len(steps) - 1
+		steps[last] = append(steps[last], args...)
+	}
+	return steps, nil
+}
+
+// substituteArgs replaces each $N in token with the Nth argument, reading all
+// the digits after the $ so that $10 is the tenth argument
+func substituteArgs(token string, args []string) (string, bool, error) {
+	var b strings.Builder
+	used := false
+
+	for i := 0; i < len(token); i++ {
+		end := i + 1
+		for end < len(token) && token[end] >= '0' && token[end] <= '9' {
+			end++
+		}
+		if token[i] != '$' || end == i+1 || token[i+1] == '0' {
+			b.WriteByte(token[i])
+			continue
+		}
+
+		n, err := strconv.Atoi(token[i+1 : end])
+		if err != nil || n > len(args) {
+			return "", false, usageErrorf("missing argument %s", token[i:end])
+		}
+		b.WriteString(args[n-1])
+		used = true
+		i = end - 1
+	}
+	return b.String(), used, nil
+}
+
+// aliasHelp returns the help section listing configured aliases
+func (c *CLIController) aliasHelp() string {
+	if len(c.config.Aliases) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(c.config.Aliases))
+	for name := range c.config.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("\nAliases:\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "  %-35s = %s\n", name, c.config.Aliases[name])
+	}
+	return b.String()
+}
+
+// aliasLoop formats the alias chain that led back to command
+func aliasLoop(expanding []string, command string) error {
+	chain := append(slices.Clone(expanding), command)
+	return usageErrorf("alias loop detected: %s", strings.Join(chain, " -> "))
+}
@@ -0,0 +1,201 @@
+package controller
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Illuminateee/task-tracker.git/delivery/terminal"
+	"github.com/Illuminateee/task-tracker.git/entity"
+)
+
+const (
+	// boardDefaultWidth is used when the terminal width cannot be determined
+	boardDefaultWidth = 80
+	// boardColumnSeparator separates adjacent status columns
+	boardColumnSeparator = " | "
+)
+
+// boardColumn is a single status column of the board
+type boardColumn struct {
+	status entity.TaskStatus
+	tasks  []*entity.Task
+}
+
+// handleBoard processes the board command
+func (c *CLIController) handleBoard(args []string) error {
+	flags := flag.NewFlagSet("board", flag.ContinueOnError)
+	maxCards := flags.Int("max", 10, "maximum tasks shown per column")
+	if err := flags.Parse(args); err != nil {
+		return usageErrorf("invalid board arguments. Usage: board [filter] [--max <n>]")
+	}
+
+	// Accept flags on either side of the filter
+	filter := c.config.DefaultFilter
+	if flags.NArg() > 0 {
+		filter = strings.ToLower(flags.Arg(0))
+		if err := flags.Parse(flags.Args()[1:]); err != nil || flags.NArg() > 0 {
+			return usageErrorf("invalid board arguments. Usage: board [filter] [--max <n>]")
+		}
+	}
+	if *maxCards < 1 {
+		return usageErrorf("--max must be at least 1")
+	}
+
+	tasks, err := c.taskManager.ListTasks(filter)
+	if err != nil {
+		return fmt.Errorf("failed to list tasks: %w", err)
+	}
+
+	columns := boardColumns(filter, tasks)
+	fmt.Print(renderBoard(columns, boardWidth(), *maxCards))
+	return nil
+}
+
+// boardColumns groups tasks into the status columns selected by filter
+func boardColumns(filter string, tasks []*entity.Task) []*boardColumn {
+	var statuses []entity.TaskStatus
+	switch filter {
+	case "todo", "in-progress", "done":
+		statuses = []entity.TaskStatus{entity.TaskStatus(filter)}
+	case "pending":
+		statuses = []entity.TaskStatus{entity.TaskStatusToDo, entity.TaskStatusInProgress}
+	default:
+		statuses = []entity.TaskStatus{entity.TaskStatusToDo, entity.TaskStatusInProgress, entity.TaskStatusDone}
+	}
+
+	columns := make([]*boardColumn, len(statuses))
+	byStatus := make(map[entity.TaskStatus]*boardColumn)
+	for i, status := range statuses {
+		columns[i] = &boardColumn{status: status}
+		byStatus[status] = columns[i]
+	}
+
+	for _, task := range tasks {
+		if column, ok := byStatus[task.Status]; ok {
+			column.tasks = append(column.tasks, task)
+		}
+	}
+	return columns
+}
+
+// boardWidth returns the terminal width, falling back to $COLUMNS and then a default
+func boardWidth() int {
+	if width, _, err := terminal.Size(int(os.Stdout.Fd())); err == nil && width > 0 {
+		return width
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return boardDefaultWidth
+}
+
+// renderBoard lays out columns side by side within width
+func renderBoard(columns []*boardColumn, width, maxCards int) string {
+	separatorWidth := len(boardColumnSeparator) * (len(columns) - 1)
+	columnWidth := (width - separatorWidth) / len(columns)
+	if columnWidth < 10 {
+		columnWidth = 10
+	}
+
+	// Build each column as a list of lines
+	columnLines := make([][]string, len(columns))
+	height := 0
+	for i, column := range columns {
+		lines := []string{
+			truncate(fmt.Sprintf("%s (%d)", strings.ToUpper(string(column.status)), len(column.tasks)), columnWidth),
+			strings.Repeat("-", columnWidth),
+		}
+
+		shown := column.tasks
+		if len(shown) > maxCards {
+			shown = shown[:maxCards]
+		}
+		for _, task := range shown {
+			lines = append(lines, boardCard(task, columnWidth)...)
+		}
+		if hidden := len(column.tasks) - len(shown); hidden > 0 {
+			lines = append(lines, fmt.Sprintf("+%d more", hidden))
+		}
+
+		columnLines[i] = lines
+		height = max(height, len(lines))
+	}
+
+	var b strings.Builder
+	for row := 0; row < height; row++ {
+		var cells []string
+		for _, lines := range columnLines {
+			cell := ""
+			if row < len(lines) {
+				cell = lines[row]
+			}
+			cells = append(cells, padRight(cell, columnWidth))
+		}
+		b.WriteString(strings.TrimRight(strings.Join(cells, boardColumnSeparator), " "))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// boardCard formats a task as wrapped lines prefixed with its ID
+func boardCard(task *entity.Task, width int) []string {
+	prefix := fmt.Sprintf("#%d ", task.ID)
+	indent := strings.Repeat(" ", len(prefix))
+
+	lines := wrapText(task.Title, width-len(prefix))
+	for i := range lines {
+		if i == 0 {
+			lines[i] = prefix + lines[i]
+		} else {
+			lines[i] = indent + lines[i]
+		}
+	}
+	return lines
+}
+
+// wrapText breaks s into lines of at most width runes, splitting long words
+func wrapText(s string, width int) []string {
+	if width < 1 {
+		width = 1
+	}
+
+	var lines []string
+	var current []rune
+	for _, word := range strings.Fields(s) {
+		runes := []rune(word)
+		for len(runes) > 0 {
+			space := 0
+			if len(current) > 0 {
+				space = 1
+			}
+			if len(current)+space+len(runes) <= width {
+				if space == 1 {
+					current = append(current, ' ')
+				}
+				current = append(current, runes...)
+				runes = nil
+			} else if len(current) > 0 {
+				lines = append(lines, string(current))
+				current = nil
+			} else {
+				lines = append(lines, string(runes[:width]))
+				runes = runes[width:]
+			}
+		}
+	}
+	if len(current) > 0 || len(lines) == 0 {
+		lines = append(lines, string(current))
+	}
+	return lines
+}
+
+// padRight pads s with spaces to width runes
+func padRight(s string, width int) string {
+	if n := len([]rune(s)); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
@@ -0,0 +1,272 @@
+package controller
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Illuminateee/task-tracker.git/delivery/terminal"
+	"github.com/Illuminateee/task-tracker.git/entity"
+)
+
+// taskSelection describes the tasks a bulk command applies to
+type taskSelection struct {
+	ids   []idRange
+	where []string
+	yes   bool
+}
+
+// idRange is an inclusive range of task IDs. A single ID must exist, while a
+// range only selects the tasks that exist within it
+type idRange struct {
+	start, end int
+	single     bool
+}
+
+// parseSelection parses task IDs, ranges (3-7), lists (1,4,9), --where
+// conditions and --yes from the arguments of a bulk command
+func parseSelection(args []string) (*taskSelection, error) {
+	selection := &taskSelection{}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--yes" || arg == "-y":
+			selection.yes = true
+		case arg == "--where":
+			if i+1 >= len(args) {
+				return nil, usageErrorf("--where requires a condition such as status:todo")
+			}
+			i++
+			selection.where = append(selection.where, args[i])
+		case strings.HasPrefix(arg, "--where="):
+			selection.where = append(selection.where, strings.TrimPrefix(arg, "--where="))
+		default:
+			for _, part := range strings.Split(arg, ",") {
+				if part == "" {
+					continue
+				}
+				ids, err := parseIDRange(part)
+				if err != nil {
+					return nil, err
+				}
+				selection.ids = append(selection.ids, ids)
+			}
+		}
+	}
+
+	if len(selection.ids) > 0 && len(selection.where) > 0 {
+		return nil, usageErrorf("task IDs and --where cannot be combined")
+	}
+	return selection, nil
+}
+
+// parseIDRange parses a single ID or an inclusive range such as 3-7
+func parseIDRange(part string) (idRange, error) {
+	startText, endText, isRange := strings.Cut(part, "-")
+	start, err := strconv.Atoi(startText)
+	if err != nil {
+		return idRange{}, usageErrorf("invalid task ID: %s", part)
+	}
+	if !isRange {
+		return idRange{start: start, end: start, single: true}, nil
+	}
+
+	end, err := strconv.Atoi(endText)
+	if err != nil || end < start {
+		return idRange{}, usageErrorf("invalid task ID range: %s", part)
+	}
+	return idRange{start: start, end: end}, nil
+}
+
+// resolveSelection returns the IDs of the selected tasks
+func (c *CLIController) resolveSelection(selection *taskSelection) ([]int, error) {
+	if len(selection.where) == 0 {
+		return c.resolveIDs(selection.ids)
+	}
+
+	statusFilter := "all"
+	var titleFilters []string
+	for _, condition := range selection.where {
+		key, value, ok := strings.Cut(condition, ":")
+		if !ok {
+			return nil, usageErrorf("invalid condition %q. Use status:<filter> or title:<text>", condition)
+		}
+		switch strings.ToLower(key) {
+		case "status":
+			statusFilter = strings.ToLower(value)
+		case "title":
+			titleFilters = append(titleFilters, strings.ToLower(value))
+		default:
+			return nil, usageErrorf("unknown condition field %q. Valid fields: status, title", key)
+		}
+	}
+
+	tasks, err := c.taskManager.ListTasks(statusFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	for _, task := range tasks {
+		matches := true
+		for _, text := range titleFilters {
+			if !strings.Contains(strings.ToLower(task.Title), text) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			ids = append(ids, task.ID)
+		}
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// resolveIDs expands ranges to the IDs of the stored tasks within them, in
+// argument order; single IDs are kept so that a missing task is reported
+func (c *CLIController) resolveIDs(ranges []idRange) ([]int, error) {
+	var existing []int
+	if slices.ContainsFunc(ranges, func(r idRange) bool { return !r.single }) {
+		tasks, err := c.taskManager.ListAllTasks()
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range tasks {
+			existing = append(existing, task.ID)
+		}
+		sort.Ints(existing)
+	}
+
+	var ids []int
+	seen := make(map[int]bool)
+	add := func(id int) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, r := range ranges {
+		if r.single {
+			add(r.start)
+			continue
+		}
+		from, _ := slices.BinarySearch(existing, r.start)
+		for _, id := range existing[from:] {
+			if id > r.end {
+				break
+			}
+			add(id)
+		}
+	}
+	return ids, nil
+}
+
+// confirmBulk asks before changing more tasks than the configured threshold
+func (c *CLIController) confirmBulk(action string, ids []int, yes bool) (bool, error) {
+	if yes || len(ids) <= c.config.ConfirmThreshold {
+		return true, nil
+	}
+
+	if !terminal.IsTerminal(int(os.Stdin.Fd())) {
+		return false, usageErrorf("refusing to %s %d tasks without confirmation. Pass --yes to proceed", action, len(ids))
+	}
+
+	fmt.Printf("About to %s %d tasks (%s). Continue? (y/N) ", action, len(ids), formatIDs(ids))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// handleMarkStatus processes the mark-done, mark-in-progress and mark-todo commands
+func (c *CLIController) handleMarkStatus(command string, status entity.TaskStatus, args []string) error {
+	label := strings.ReplaceAll(string(status), "-", " ")
+	usage := fmt.Sprintf("Usage: %s <id>[,<id>|<from>-<to>]... | --where <field>:<value> [--yes]", command)
+
+	selection, err := parseSelection(args)
+	if err != nil {
+		return fmt.Errorf("%w. %s", err, usage)
+	}
+	if len(selection.ids) == 0 && len(selection.where) == 0 {
+		return usageErrorf("%s command requires a task ID. %s", command, usage)
+	}
+
+	ids, err := c.resolveSelection(selection)
+	if err != nil {
+		return fmt.Errorf("failed to mark task as %s: %w", label, err)
+	}
+	if len(ids) == 0 {
+		fmt.Println("No tasks matched")
+		return nil
+	}
+
+	ok, err := c.confirmBulk("mark as "+label, ids, selection.yes)
+	if err != nil || !ok {
+		return err
+	}
+
+	tasks, err := c.taskManager.UpdateTasksStatus(ids, string(status))
+	if err != nil {
+		return fmt.Errorf("failed to mark task as %s: %w", label, err)
+	}
+
+	if len(tasks) == 1 {
+		fmt.Printf("Task marked as %s\n", label)
+		c.printTask(tasks[0])
+		return nil
+	}
+	fmt.Printf("%d tasks marked as %s: %s\n", len(tasks), label, formatIDs(ids))
+	return nil
+}
+
+// handleDelete processes the delete command
+func (c *CLIController) handleDelete(args []string) error {
+	usage := "Usage: delete <id>[,<id>|<from>-<to>]... | --where <field>:<value> [--yes]"
+
+	selection, err := parseSelection(args)
+	if err != nil {
+		return fmt.Errorf("%w. %s", err, usage)
+	}
+	if len(selection.ids) == 0 && len(selection.where) == 0 {
+		return usageErrorf("delete command requires a task ID. %s", usage)
+	}
+
+	ids, err := c.resolveSelection(selection)
+	if err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	if len(ids) == 0 {
+		fmt.Println("No tasks matched")
+		return nil
+	}
+
+	ok, err := c.confirmBulk("delete", ids, selection.yes)
+	if err != nil || !ok {
+		return err
+	}
+
+	if err := c.taskManager.DeleteTasks(ids); err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+
+	if len(ids) == 1 {
+		fmt.Printf("Task %d deleted successfully\n", ids[0])
+		return nil
+	}
+	fmt.Printf("%d tasks deleted successfully: %s\n", len(ids), formatIDs(ids))
+	return nil
+}
+
+// formatIDs joins task IDs with commas
+func formatIDs(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ", ")
+}
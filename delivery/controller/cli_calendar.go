@@ -0,0 +1,210 @@
+package controller
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+	"github.com/Illuminateee/task-tracker.git/usecase"
+)
+
+// calendarUsage describes the calendar command
+const calendarUsage = "Usage: calendar [YYYY-MM|month] | calendar --week [date|+1w|-1w]"
+
+// calendarCellWidth fits a day, its task count and the overdue mark, e.g.
+// "15 (99+)!", plus a space
+const calendarCellWidth = 10
+
+// calendarMaxCount is the largest task count a day shows; more show as "99+"
+const calendarMaxCount = 99
+
+// handleCalendar processes the calendar command
+func (c *CLIController) handleCalendar(args []string) error {
+	flags := flag.NewFlagSet("calendar", flag.ContinueOnError)
+	week := flags.Bool("week", false, "list the tasks due on each day of a week")
+
+	// Take a negative offset such as -1w out before the flag package mistakes it for a flag
+	now := time.Now()
+	var positional, rest []string
+	for _, arg := range args {
+		if offset, ok := strings.CutPrefix(arg, "-"); ok {
+			if _, valid := addOffset(now, offset, -1); valid {
+				positional = append(positional, arg)
+				continue
+			}
+		}
+		rest = append(rest, arg)
+	}
+	if err := flags.Parse(rest); err != nil {
+		return usageErrorf("invalid calendar arguments. %s", calendarUsage)
+	}
+	positional = append(positional, flags.Args()...)
+	if len(positional) > 1 {
+		return usageErrorf("invalid calendar arguments. %s", calendarUsage)
+	}
+
+	if *week {
+		day := now
+		if len(positional) == 1 {
+			parsed, err := c.parseCalendarDay(positional[0], now)
+			if err != nil {
+				return err
+			}
+			day = parsed
+		}
+		return c.printCalendarWeek(usecase.StartOfWeek(day), now)
+	}
+
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	if len(positional) == 1 {
+		parsed, err := parseCalendarMonth(positional[0], now)
+		if err != nil {
+			return err
+		}
+		month = parsed
+	}
+	return c.printCalendarMonth(month, now)
+}
+
+// parseCalendarMonth parses a month given as YYYY-MM, or as a number or name in now's year
+func parseCalendarMonth(value string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01", value, time.Local); err == nil {
+		return t, nil
+	}
+	if n, err := strconv.Atoi(value); err == nil && n >= 1 && n <= 12 {
+		return time.Date(now.Year(), time.Month(n), 1, 0, 0, 0, 0, time.Local), nil
+	}
+	for month := time.January; month <= time.December; month++ {
+		if strings.EqualFold(value, month.String()) || strings.EqualFold(value, month.String()[:3]) {
+			return time.Date(now.Year(), month, 1, 0, 0, 0, 0, time.Local), nil
+		}
+	}
+	return time.Time{}, usageErrorf("invalid month: %s. %s", value, calendarUsage)
+}
+
+// parseCalendarDay parses the day whose week to show: a date, today,
+// yesterday or an offset such as +1w or -1w
+func (c *CLIController) parseCalendarDay(value string, now time.Time) (time.Time, error) {
+	if offset, ok := strings.CutPrefix(value, "+"); ok {
+		if day, ok := addOffset(now, offset, 1); ok {
+			return day, nil
+		}
+		return time.Time{}, usageErrorf("invalid calendar offset: %s. Use e.g. +1w or -1w", value)
+	}
+	return c.parseReportTime(value, now)
+}
+
+// printCalendarMonth draws the month as a grid of weeks, each day showing
+// how many unfinished tasks are due that day
+func (c *CLIController) printCalendarMonth(month, now time.Time) error {
+	tasks, err := c.taskManager.ListTasksDueBetween(month, month.AddDate(0, 1, 0))
+	if err != nil {
+		return fmt.Errorf("failed to get due tasks: %w", err)
+	}
+	writeCalendarMonth(os.Stdout, month, now, tasks, c.color)
+	return nil
+}
+
+// writeCalendarMonth writes the grid of printCalendarMonth for the tasks due in month
+func writeCalendarMonth(w io.Writer, month, now time.Time, tasks []*entity.Task, color bool) {
+	counts := make(map[int]int)
+	overdue := make(map[int]bool)
+	totalOverdue := 0
+	for _, task := range tasks {
+		day := task.Due.Local().Day()
+		counts[day]++
+		if task.IsOverdue(now) {
+			overdue[day] = true
+			totalOverdue++
+		}
+	}
+
+	title := month.Format("January 2006")
+	fmt.Fprintf(w, "%*s\n", (7*calendarCellWidth+len(title))/2, title)
+	fmt.Fprintln(w, strings.Join([]string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}, strings.Repeat(" ", calendarCellWidth-3)))
+
+	// Weeks start on Monday; pad the first row up to the month's first weekday
+	next := month.AddDate(0, 1, 0)
+	column := (int(month.Weekday()) + 6) % 7
+	row := strings.Repeat(" ", column*calendarCellWidth)
+	today := now.Local()
+	last := next.AddDate(0, 0, -1).Day()
+	for day := 1; day <= last; day++ {
+		cell := strconv.Itoa(day)
+		switch count := counts[day]; {
+		case count > calendarMaxCount:
+			cell += fmt.Sprintf(" (%d+)", calendarMaxCount)
+		case count > 0:
+			cell += fmt.Sprintf(" (%d)", count)
+		}
+		if overdue[day] && !color {
+			cell += "!"
+		}
+		padding := strings.Repeat(" ", max(calendarCellWidth-len(cell), 1))
+
+		isToday := today.Year() == month.Year() && today.Month() == month.Month() && today.Day() == day
+		switch {
+		case !color:
+		case overdue[day]:
+			cell = "\x1b[31m" + cell + ansiReset
+		case isToday:
+			cell = "\x1b[7m" + cell + ansiReset
+		}
+		row += cell + padding
+
+		column++
+		if column == 7 || day == last {
+			fmt.Fprintln(w, strings.TrimRight(row, " "))
+			row, column = "", 0
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d task(s) due", len(tasks))
+	if totalOverdue > 0 {
+		fmt.Fprintf(w, ", %d overdue", totalOverdue)
+	}
+	fmt.Fprintln(w)
+}
+
+// printCalendarWeek lists the unfinished tasks due on each day of the week starting on start
+func (c *CLIController) printCalendarWeek(start, now time.Time) error {
+	tasks, err := c.taskManager.ListTasksDueBetween(start, start.AddDate(0, 0, 7))
+	if err != nil {
+		return fmt.Errorf("failed to get due tasks: %w", err)
+	}
+
+	byDay := make(map[string][]*entity.Task)
+	for _, task := range tasks {
+		day := task.Due.Local().Format(time.DateOnly)
+		byDay[day] = append(byDay[day], task)
+	}
+
+	for i := range 7 {
+		day := start.AddDate(0, 0, i)
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(day.Format("Mon 2006-01-02"))
+		dayTasks := byDay[day.Format(time.DateOnly)]
+		if len(dayTasks) == 0 {
+			fmt.Println("  no tasks due")
+		}
+		for _, task := range dayTasks {
+			line := fmt.Sprintf("  %s  #%d %s", task.Due.Local().Format("15:04"), task.ID, task.Title)
+			if task.IsOverdue(now) {
+				if c.color {
+					line = "\x1b[31m" + line + " (overdue)" + ansiReset
+				} else {
+					line += " (overdue)"
+				}
+			}
+			fmt.Println(line)
+		}
+	}
+	return nil
+}
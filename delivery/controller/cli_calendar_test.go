@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+)
+
+// dueTasks returns n todo tasks due on due
+func dueTasks(n int, due time.Time) []*entity.Task {
+	tasks := make([]*entity.Task, n)
+	for i := range tasks {
+		tasks[i] = &entity.Task{ID: i + 1, Title: "Task", Status: entity.TaskStatusToDo, Due: &due}
+	}
+	return tasks
+}
+
+func TestCalendarMonthKeepsColumnsAligned(t *testing.T) {
+	month := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.Local)
+	now := time.Date(2026, time.October, 20, 12, 0, 0, 0, time.Local)
+
+	// Overdue days with two- and three-digit counts are the widest cells
+	tasks := dueTasks(12, time.Date(2026, time.October, 15, 9, 0, 0, 0, time.Local))
+	tasks = append(tasks, dueTasks(150, time.Date(2026, time.October, 16, 9, 0, 0, 0, time.Local))...)
+
+	var out strings.Builder
+	writeCalendarMonth(&out, month, now, tasks, false)
+	lines := strings.Split(out.String(), "\n")
+
+	// October 2026 starts on a Thursday: the weeks are in lines 2 to 6
+	want := []string{
+		"12        13        14        15 (12)!  16 (99+)! 17        18",
+		"19        20        21        22        23        24        25",
+	}
+	for i, line := range want {
+		if got := lines[4+i]; got != line {
+			t.Errorf("week line %d = %q, want %q", 4+i, got, line)
+		}
+	}
+
+	// Every day starts at a multiple of the cell width in every week
+	for _, line := range lines[2:7] {
+		for column := 0; column < len(line); column += calendarCellWidth {
+			if line[column] == ' ' && strings.TrimSpace(line[:column]) != "" {
+				t.Errorf("day cell at column %d of %q is misaligned", column, line)
+			}
+			if column > 0 && line[column-1] != ' ' {
+				t.Errorf("cell ending at column %d of %q runs into the next one", column, line)
+			}
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Illuminateee/task-tracker.git/delivery/terminal"
+)
+
+const bashCompletionScript = `# bash completion for task-tracker
+_task_tracker() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local IFS=$'\n'
+    COMPREPLY=($(task-tracker __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null | cut -f1))
+}
+complete -o default -F _task_tracker task-tracker
+`
+
+const zshCompletionScript = `#compdef task-tracker
+# zsh completion for task-tracker
+_task_tracker() {
+    local -a candidates
+    local line value description
+    for line in "${(@f)$(task-tracker __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}"; do
+        [[ -z $line ]] && continue
+        value="${line%%$'\t'*}"
+        description=""
+        [[ $line == *$'\t'* ]] && description="${line#*$'\t'}"
+        candidates+=("${value//:/\\:}${description:+:$description}")
+    done
+    _describe 'task-tracker' candidates
+}
+compdef _task_tracker task-tracker
+`
+
+const fishCompletionScript = `# fish completion for task-tracker
+function __task_tracker_complete
+    set -l tokens (commandline -opc) (commandline -ct)
+    task-tracker __complete $tokens[2..-1] 2>/dev/null
+end
+complete -c task-tracker -f -a '(__task_tracker_complete)'
+`
+
+// handleCompletion processes the completion command
+func (c *CLIController) handleCompletion(args []string) error {
+	if len(args) != 1 {
+		return usageErrorf("completion command requires a shell. Usage: completion bash|zsh|fish")
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "bash":
+		fmt.Print(bashCompletionScript)
+	case "zsh":
+		fmt.Print(zshCompletionScript)
+	case "fish":
+		fmt.Print(fishCompletionScript)
+	default:
+		return usageErrorf("unsupported shell: %s. Supported shells: bash, zsh, fish", args[0])
+	}
+	return nil
+}
+
+// handleComplete processes the hidden __complete command used by completion scripts;
+// the last argument is the word being completed
+func (c *CLIController) handleComplete(args []string) error {
+	word := ""
+	if len(args) > 0 {
+		word = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
+	for _, candidate := range c.completeArgs(args, word) {
+		if candidate.Description != "" {
+			fmt.Printf("%s\t%s\n", candidate.Value, candidate.Description)
+		} else {
+			fmt.Println(candidate.Value)
+		}
+	}
+	return nil
+}
+
+// completeArgs returns candidates for word given the preceding arguments
+func (c *CLIController) completeArgs(args []string, word string) []terminal.Completion {
+	prefix := strings.TrimLeft(word, `"'`)
+
+	if len(args) == 0 {
+		aliases := make([]string, 0, len(c.config.Aliases))
+		for name := range c.config.Aliases {
+			aliases = append(aliases, name)
+		}
+		sort.Strings(aliases)
+		candidates := matchWords(commandNames, prefix, nil)
+		candidates = matchWords(c.pluginNames(), prefix, candidates)
+		return matchWords(aliases, prefix, candidates)
+	}
+
+	command := strings.ToLower(args[0])
+	switch {
+	case len(args) == 1 && (command == "list" || command == "board"):
+		return matchWords(listFilters, prefix, nil)
+	case len(args) == 1 && command == "completion":
+		return matchWords([]string{"bash", "zsh", "fish"}, prefix, nil)
+	case len(args) == 1 && command == "storage":
+		return matchWords([]string{"info", "convert", "encrypt", "decrypt", "rekey"}, prefix, nil)
+	case len(args) == 1 && command == "report":
+		return matchWords([]string{"standup", "week"}, prefix, nil)
+	case command == "report" && args[len(args)-1] == "--format":
+		return matchWords([]string{"text", "markdown"}, prefix, nil)
+	case len(args) == 1 && command == "webhooks":
+		return matchWords([]string{"list", "flush", "drop"}, prefix, nil)
+	case command == "storage" && args[len(args)-1] == "--to":
+		return matchWords([]string{"json", "binary"}, prefix, nil)
+	case (command == "update" || command == "due") && len(args) == 1:
+		return c.completeTaskIDs(prefix)
+	case command == "due" && len(args) == 2:
+		return matchWords([]string{"today", "tomorrow", "clear"}, prefix, nil)
+	case command != "update" && isTaskIDCommand(command) && !strings.HasPrefix(word, "-"):
+		// Bulk commands accept any number of IDs
+		return c.completeTaskIDs(prefix)
+	case len(args) == 2 && command == "update":
+		// Offer the current title so it can be edited in place
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			return nil
+		}
+		task, err := c.taskManager.GetTask(id)
+		if err != nil || !strings.HasPrefix(strings.ToLower(task.Title), strings.ToLower(prefix)) {
+			return nil
+		}
+		return []terminal.Completion{{Value: quoteArg(task.Title)}}
+	}
+	return nil
+}
+
+// completeTaskIDs returns task IDs whose ID starts with, or title contains, prefix
+func (c *CLIController) completeTaskIDs(prefix string) []terminal.Completion {
+	lowerPrefix := strings.ToLower(prefix)
+	var candidates []terminal.Completion
+	for task, err := range c.taskManager.IterateTasks() {
+		if err != nil {
+			return nil
+		}
+		id := strconv.Itoa(task.ID)
+		if strings.HasPrefix(id, prefix) || strings.Contains(strings.ToLower(task.Title), lowerPrefix) {
+			candidates = append(candidates, terminal.Completion{Value: id, Description: task.Title})
+		}
+	}
+	return candidates
+}
+
+// matchWords appends the words that start with prefix to candidates
+func matchWords(words []string, prefix string, candidates []terminal.Completion) []terminal.Completion {
+	for _, word := range words {
+		if strings.HasPrefix(word, strings.ToLower(prefix)) {
+			candidates = append(candidates, terminal.Completion{Value: word})
+		}
+	}
+	return candidates
+}
@@ -1,34 +1,87 @@
 package controller
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
 	"strconv"
 	"strings"
-	"time"
 
+	"github.com/Illuminateee/task-tracker.git/config"
+	"github.com/Illuminateee/task-tracker.git/delivery/terminal"
 	"github.com/Illuminateee/task-tracker.git/entity"
+	"github.com/Illuminateee/task-tracker.git/hook"
 	"github.com/Illuminateee/task-tracker.git/manager"
+	"github.com/Illuminateee/task-tracker.git/repository"
 )
 
+// commandNames lists the commands accepted by HandleCommand
+var commandNames = []string{
+	"add", "update", "delete", "mark-done", "mark-in-progress", "mark-todo",
+	"due", "list", "board", "calendar", "stats", "report", "serve", "rpc", "tui", "shell", "completion", "config", "storage", "merge", "sync", "sync-server", "watch", "daemon", "webhooks", "help",
+}
+
+// apiTokenEnv holds the REST API token when it is not in the config file
+const apiTokenEnv = "TASK_TRACKER_API_TOKEN"
+
+// listFilters lists the filters accepted by list and board
+var listFilters = []string{"all", "done", "todo", "in-progress", "pending"}
+
 // CLIController handles command line interface operations
 type CLIController struct {
-	taskManager *manager.TaskManager
+	taskManager  *manager.TaskManager
+	dataFilePath string
+	config       *config.Config
+	color        bool
+	// warnings reports failures that do not fail a command, such as
+	// background webhook deliveries; the board and the shell hold them while
+	// the terminal is in raw mode
+	warnings *log.Logger
 }
 
-// NewCLIController creates a new CLI controller
-func NewCLIController(dataFilePath string) *CLIController {
-	return &CLIController{
-		taskManager: manager.NewTaskManager(dataFilePath),
+// NewCLIController creates a new CLI controller from the loaded configuration
+func NewCLIController(cfg *config.Config) *CLIController {
+	c := &CLIController{
+		dataFilePath: cfg.DataFile,
+		config:       cfg,
+		color:        useColor(cfg.Color),
+		warnings:     log.New(os.Stderr, "Warning: ", 0),
+	}
+	c.taskManager = manager.NewTaskManager(cfg.DataFile, repository.FileOptions{
+		Format: repository.Format(cfg.StorageFormat),
+		Secret: c.openSecret,
+	})
+	c.taskManager.AddHook(hook.NewScriptHook(cfg.HooksDir))
+	if len(cfg.Webhooks) > 0 {
+		c.taskManager.EnableWebhooks(cfg.Webhooks, newWebhookPost(cfg.WebhookSecret), c.warn)
 	}
+	return c
 }
 
 // HandleCommand processes CLI commands and arguments
 func (c *CLIController) HandleCommand(args []string) error {
+	return c.dispatch(args, nil)
+}
+
+// dispatch expands aliases and runs the matching command; expanding holds the
+// aliases already being expanded so that loops can be detected
+func (c *CLIController) dispatch(args []string, expanding []string) error {
 	if len(args) == 0 {
 		return c.showHelp()
 	}
 
 	command := strings.ToLower(args[0])
+	// An alias may wrap the built-in command of the same name, as in shells
+	if _, ok := c.config.Aliases[command]; ok && !slices.Contains(expanding, command) {
+		return c.runAlias(command, args[1:], append(expanding, command))
+	}
+
 	switch command {
 	case "add":
 		return c.handleAdd(args[1:])
@@ -37,24 +90,66 @@ func (c *CLIController) HandleCommand(args []string) error {
 	case "delete":
 		return c.handleDelete(args[1:])
 	case "mark-done":
-		return c.handleMarkDone(args[1:])
+		return c.handleMarkStatus(command, entity.TaskStatusDone, args[1:])
 	case "mark-in-progress":
-		return c.handleMarkInProgress(args[1:])
+		return c.handleMarkStatus(command, entity.TaskStatusInProgress, args[1:])
 	case "mark-todo":
-		return c.handleMarkTodo(args[1:])
+		return c.handleMarkStatus(command, entity.TaskStatusToDo, args[1:])
+	case "due":
+		return c.handleDue(args[1:])
 	case "list":
 		return c.handleList(args[1:])
+	case "board":
+		return c.handleBoard(args[1:])
+	case "calendar":
+		return c.handleCalendar(args[1:])
+	case "stats":
+		return c.handleStats(args[1:])
+	case "report":
+		return c.handleReport(args[1:])
+	case "serve":
+		return c.handleServe(args[1:])
+	case "rpc":
+		return c.handleRPC(args[1:])
+	case "shell":
+		return c.handleShell(args[1:])
+	case "tui":
+		return c.handleTUI(args[1:])
+	case "config":
+		return c.handleConfig(args[1:])
+	case "storage":
+		return c.handleStorage(args[1:])
+	case "merge":
+		return c.handleMerge(args[1:])
+	case "sync":
+		return c.handleSync(args[1:])
+	case "sync-server":
+		return c.handleSyncServer(args[1:])
+	case "watch", "daemon":
+		return c.handleWatch(args[1:])
+	case "webhooks":
+		return c.handleWebhooks(args[1:])
+	case "completion":
+		return c.handleCompletion(args[1:])
+	case "__complete":
+		return c.handleComplete(args[1:])
 	case "help", "-h", "--help":
 		return c.showHelp()
 	default:
-		return fmt.Errorf("unknown command: %s. Use 'help' to see available commands", command)
+		if slices.Contains(expanding, command) {
+			return aliasLoop(expanding, command)
+		}
+		if path, ok := c.findPlugin(command); ok {
+			return c.runPlugin(path, command, args[1:])
+		}
+		return usageErrorf("unknown command: %s. Use 'help' to see available commands", command)
 	}
 }
 
 // handleAdd processes the add command
 func (c *CLIController) handleAdd(args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("add command requires a title. Usage: add \"<title>\" [\"<description>\"]")
+		return usageErrorf("add command requires a title. Usage: add \"<title>\" [\"<description>\"]")
 	}
 
 	title := args[0]
@@ -76,12 +171,12 @@ func (c *CLIController) handleAdd(args []string) error {
 // handleUpdate processes the update command
 func (c *CLIController) handleUpdate(args []string) error {
 	if len(args) < 2 {
-		return fmt.Errorf("update command requires ID and title. Usage: update <id> \"<title>\" [\"<description>\"]")
+		return usageErrorf("update command requires ID and title. Usage: update <id> \"<title>\" [\"<description>\"]")
 	}
 
 	id, err := strconv.Atoi(args[0])
 	if err != nil {
-		return fmt.Errorf("invalid task ID: %s", args[0])
+		return usageErrorf("invalid task ID: %s", args[0])
 	}
 
 	title := args[1]
@@ -100,119 +195,196 @@ func (c *CLIController) handleUpdate(args []string) error {
 	return nil
 }
 
-// handleDelete processes the delete command
-func (c *CLIController) handleDelete(args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("delete command requires a task ID. Usage: delete <id>")
+// handleList processes the list command
+func (c *CLIController) handleList(args []string) error {
+	const usage = "invalid list arguments. Usage: list [filter] [--limit <n>] [--page <n>]"
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	limit := flags.Int("limit", 0, "maximum tasks shown per page")
+	page := flags.Int("page", 1, "page to show, starting at 1")
+	if err := flags.Parse(args); err != nil {
+		return usageErrorf(usage)
 	}
 
-	id, err := strconv.Atoi(args[0])
-	if err != nil {
-		return fmt.Errorf("invalid task ID: %s", args[0])
+	// Accept flags on either side of the filter
+	filter := c.config.DefaultFilter
+	if flags.NArg() > 0 {
+		filter = strings.ToLower(flags.Arg(0))
+		if err := flags.Parse(flags.Args()[1:]); err != nil || flags.NArg() > 0 {
+			return usageErrorf(usage)
+		}
+	}
+	if *limit < 0 {
+		return usageErrorf("--limit cannot be negative")
+	}
+	if *page < 1 {
+		return usageErrorf("--page must be at least 1")
 	}
 
-	if err := c.taskManager.DeleteTask(id); err != nil {
-		return fmt.Errorf("failed to delete task: %w", err)
+	if *limit == 0 && *page == 1 {
+		tasks, err := c.taskManager.ListTasks(filter)
+		if err != nil {
+			return fmt.Errorf("failed to list tasks: %w", err)
+		}
+		c.printTaskList(tasks, filter)
+		return nil
 	}
 
-	fmt.Printf("Task %d deleted successfully\n", id)
+	result, err := c.taskManager.ListTasksPage(filter, *page, *limit)
+	if err != nil {
+		return fmt.Errorf("failed to list tasks: %w", err)
+	}
+	c.printTaskList(result.Tasks, filter)
+	if *limit > 0 && result.Total > 0 {
+		pages := (result.Total + *limit - 1) / *limit
+		fmt.Printf("\nPage %d of %d (%d tasks)\n", *page, pages, result.Total)
+	}
 	return nil
 }
 
-// handleMarkDone processes the mark-done command
-func (c *CLIController) handleMarkDone(args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("mark-done command requires a task ID. Usage: mark-done <id>")
+// handleConfig processes the config command, printing the effective settings
+func (c *CLIController) handleConfig(args []string) error {
+	if len(args) > 0 {
+		return usageErrorf("config command takes no arguments. Usage: config")
 	}
 
-	id, err := strconv.Atoi(args[0])
-	if err != nil {
-		return fmt.Errorf("invalid task ID: %s", args[0])
+	path := c.config.Path
+	if path == "" {
+		path = "(none)"
 	}
-
-	task, err := c.taskManager.MarkDone(id)
-	if err != nil {
-		return fmt.Errorf("failed to mark task as done: %w", err)
+	profile := c.config.Profile
+	if profile == "" {
+		profile = "(none)"
 	}
 
-	fmt.Printf("Task marked as done\n")
-	c.printTask(task)
+	fmt.Printf("Config file: %s\n", path)
+	fmt.Printf("Profile: %s\n", profile)
+	fmt.Printf("Data file: %s\n", c.dataFilePath)
+	fmt.Printf("Default filter: %s\n", c.config.DefaultFilter)
+	fmt.Printf("Date format: %s\n", c.config.DateFormat)
+	fmt.Printf("Color: %s\n", c.config.Color)
+	fmt.Printf("Storage format: %s\n", c.config.StorageFormat)
+	syncURL := c.config.SyncURL
+	if syncURL == "" {
+		syncURL = "(none)"
+	}
+	fmt.Printf("Sync URL: %s\n", syncURL)
+	fmt.Printf("Remind before: %s\n", c.config.RemindBefore)
+	fmt.Printf("Hooks directory: %s\n", c.config.HooksDir)
+	fmt.Printf("Plugins directory: %s\n", c.config.PluginsDir)
+	webhooks := strings.Join(c.config.Webhooks, ", ")
+	if webhooks == "" {
+		webhooks = "(none)"
+	}
+	fmt.Printf("Webhooks: %s\n", webhooks)
 	return nil
 }
 
-// handleMarkInProgress processes the mark-in-progress command
-func (c *CLIController) handleMarkInProgress(args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("mark-in-progress command requires a task ID. Usage: mark-in-progress <id>")
+// handleServe processes the serve command
+func (c *CLIController) handleServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flags.String("addr", "127.0.0.1:8080", "address to listen on")
+	token := flags.String("token", "", "token clients must send")
+	if err := flags.Parse(args); err != nil {
+		return usageErrorf("invalid serve arguments. Usage: serve [--addr <host:port>] [--token <token>]")
 	}
-
-	id, err := strconv.Atoi(args[0])
-	if err != nil {
-		return fmt.Errorf("invalid task ID: %s", args[0])
+	if *token == "" {
+		*token = c.apiToken()
+	}
+	// Without a token anyone who can reach the server could change every task
+	if *token == "" && !isLoopbackAddr(*addr) {
+		return usageErrorf("refusing to serve the task API on %s without a token. Set api_token, %s or --token, or listen on 127.0.0.1", *addr, apiTokenEnv)
+	}
+	if err := c.unlockStorage(); err != nil {
+		return err
 	}
 
-	task, err := c.taskManager.MarkInProgress(id)
-	if err != nil {
-		return fmt.Errorf("failed to mark task as in progress: %w", err)
+	server := &http.Server{
+		Addr:    *addr,
+		Handler: NewHTTPController(c.taskManager, *token).Routes(),
 	}
 
-	fmt.Printf("Task marked as in progress\n")
-	c.printTask(task)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+	go c.retryWebhooks(ctx)
+
+	fmt.Printf("Serving task API on %s\n", *addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve: %w", err)
+	}
 	return nil
 }
 
-// handleMarkTodo processes the mark-todo command
-func (c *CLIController) handleMarkTodo(args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("mark-todo command requires a task ID. Usage: mark-todo <id>")
+// apiToken returns the configured API token, falling back to the environment
+func (c *CLIController) apiToken() string {
+	if c.config.APIToken != "" {
+		return c.config.APIToken
 	}
+	return os.Getenv(apiTokenEnv)
+}
 
-	id, err := strconv.Atoi(args[0])
-	if err != nil {
-		return fmt.Errorf("invalid task ID: %s", args[0])
+// handleRPC processes the rpc command
+func (c *CLIController) handleRPC(args []string) error {
+	if len(args) > 0 {
+		return usageErrorf("rpc command takes no arguments. Usage: rpc")
 	}
-
-	task, err := c.taskManager.MarkTodo(id)
-	if err != nil {
-		return fmt.Errorf("failed to mark task as todo: %w", err)
+	if err := c.unlockStorage(); err != nil {
+		return err
 	}
 
-	fmt.Printf("Task marked as todo\n")
-	c.printTask(task)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	rpcController := NewRPCController(c.taskManager, c.dataFilePath)
+	if err := rpcController.Serve(ctx, os.Stdin, os.Stdout); err != nil {
+		return fmt.Errorf("rpc session failed: %w", err)
+	}
 	return nil
 }
 
-// handleList processes the list command
-func (c *CLIController) handleList(args []string) error {
-	filter := "all"
+// handleTUI processes the tui command
+func (c *CLIController) handleTUI(args []string) error {
 	if len(args) > 0 {
-		filter = strings.ToLower(args[0])
-	}
-
-	var tasks []*entity.Task
-	var err error
-
-	switch filter {
-	case "all":
-		tasks, err = c.taskManager.ListAllTasks()
-	case "done":
-		tasks, err = c.taskManager.ListDoneTasks()
-	case "todo":
-		tasks, err = c.taskManager.ListTodoTasks()
-	case "in-progress":
-		tasks, err = c.taskManager.ListInProgressTasks()
-	case "pending":
-		tasks, err = c.taskManager.ListPendingTasks()
-	default:
-		return fmt.Errorf("invalid filter: %s. Valid filters: all, done, todo, in-progress, pending", filter)
+		return usageErrorf("tui command takes no arguments. Usage: tui")
+	}
+	if err := c.unlockStorage(); err != nil {
+		return err
 	}
+	return NewTUIController(c.taskManager, c.warnings).Run()
+}
 
-	if err != nil {
-		return fmt.Errorf("failed to list tasks: %w", err)
+// handleShell processes the shell command
+func (c *CLIController) handleShell(args []string) error {
+	if len(args) > 0 {
+		return usageErrorf("shell command takes no arguments. Usage: shell")
 	}
+	return NewShellController(c).Run()
+}
 
-	c.printTaskList(tasks, filter)
-	return nil
+// useColor resolves a color mode against the environment and output terminal
+func useColor(mode string) bool {
+	switch mode {
+	case config.ColorAlways:
+		return true
+	case config.ColorNever:
+		return false
+	default:
+		return os.Getenv("NO_COLOR") == "" && terminal.IsTerminal(int(os.Stdout.Fd()))
+	}
+}
+
+// isTaskIDCommand reports whether command takes a task ID as its first argument
+func isTaskIDCommand(command string) bool {
+	switch command {
+	case "update", "delete", "mark-done", "mark-in-progress", "mark-todo":
+		return true
+	default:
+		return false
+	}
 }
 
 // printTask prints a single task in a formatted way
@@ -222,9 +394,30 @@ func (c *CLIController) printTask(task *entity.Task) {
 	if task.Description != "" {
 		fmt.Printf("Description: %s\n", task.Description)
 	}
-	fmt.Printf("Status: %s\n", task.Status)
-	fmt.Printf("Created: %s\n", task.CreatedAt.Format(time.RFC3339))
-	fmt.Printf("Updated: %s\n", task.UpdatedAt.Format(time.RFC3339))
+	fmt.Printf("Status: %s\n", c.formatStatus(task.Status))
+	if task.Due != nil {
+		fmt.Printf("Due: %s\n", c.formatDue(task))
+	}
+	fmt.Printf("Created: %s\n", task.CreatedAt.Format(c.config.DateFormat))
+	fmt.Printf("Updated: %s\n", task.UpdatedAt.Format(c.config.DateFormat))
+}
+
+// formatStatus renders a status, colored when color output is enabled
+func (c *CLIController) formatStatus(status entity.TaskStatus) string {
+	if !c.color {
+		return string(status)
+	}
+
+	switch status {
+	case entity.TaskStatusToDo:
+		return "\x1b[33m" + string(status) + ansiReset
+	case entity.TaskStatusInProgress:
+		return "\x1b[36m" + string(status) + ansiReset
+	case entity.TaskStatusDone:
+		return "\x1b[32m" + string(status) + ansiReset
+	default:
+		return string(status)
+	}
 }
 
 // printTaskList prints a list of tasks
@@ -256,31 +449,83 @@ func (c *CLIController) printTaskList(tasks []*entity.Task, filter string) {
 func (c *CLIController) showHelp() error {
 	helpText := `Task Tracker CLI
 
-Usage: task-tracker <command> [arguments]
+Usage: task-tracker [global flags] <command> [arguments]
+
+Global flags:
+  --config <path>                     Read settings from this config file
+  --profile <name>                    Apply the settings of a config profile
+  --data <path>                       Store tasks in this file
+  --json-errors                       Report errors on stderr as JSON:
+                                      {"error": {"code": ..., "message": ..., "exit_code": ...}}
 
 Commands:
   add "<title>" ["<description>"]     Add a new task
   update <id> "<title>" ["<desc>"]    Update an existing task
-  delete <id>                         Delete a task
-  mark-done <id>                      Mark task as completed
-  mark-in-progress <id>               Mark task as in progress
-  mark-todo <id>                      Mark task as todo
-  list [filter]                       List tasks (filters: all, done, todo, in-progress, pending)
+  delete <ids>                        Delete tasks
+  mark-done <ids>                     Mark tasks as completed
+  mark-in-progress <ids>              Mark tasks as in progress
+  mark-todo <ids>                     Mark tasks as todo
+  due <id> <date|clear>               Set or clear a due date (2026-05-01, +2h, +3d, tomorrow)
+  list [filter] [--limit n] [--page n]
+                                      List tasks (filters: all, done, todo, in-progress, pending)
+  board [filter] [--max <n>]          Show tasks in columns per status
+  calendar [YYYY-MM|month]            Show a month with the number of tasks due each day
+  calendar --week [date|+1w|-1w]      List the tasks due on each day of a week
+  stats [--weeks 8] [--oldest 5]      Show counts, weekly throughput, cycle and lead times
+  report standup|week [--since <time>] [--until <time>] [--format text|markdown]
+                                      Summarize what was done, what is in progress and what is overdue
+  serve [--addr 127.0.0.1:8080] [--token <token>]
+                                      Serve the task API over HTTP
+  rpc                                 Speak JSON-RPC 2.0 over stdin/stdout
+  tui                                 Open the interactive terminal board
+  shell                               Open an interactive command prompt
+  completion bash|zsh|fish            Print a shell completion script
+  config                              Show the effective configuration
+  storage [info]                      Show the tasks file location, format and size
+  storage convert --to json|binary    Rewrite the tasks file in another format
+  storage encrypt|rekey [--key-file f]
+                                      Encrypt the tasks file, or change its key
+  storage decrypt                     Store the tasks file as plaintext again
+  merge <base> <ours> <theirs>        Three-way merge task files, writing over ours
+  merge --install [pattern]           Register merge as the git merge driver
+  sync [--url <url>]                  Push local changes to the sync server and pull remote ones
+  sync-server [--addr :8081] [--store <file>] [--token <token>]
+                                      Serve the sync endpoint for other devices
+  watch|daemon [--interval 1m] [--before 1h] [--once]
+                                      Send reminders for upcoming and overdue tasks
+  webhooks [list|flush [--all]|drop]  Show, deliver or drop queued webhook deliveries
   help                                Show this help message
 
 Examples:
   task-tracker add "Buy groceries" "Milk, bread, eggs"
   task-tracker update 1 "Buy groceries" "Milk, bread, eggs, cheese"
   task-tracker mark-done 1
+  task-tracker mark-done 3-7,9
+  task-tracker delete --where status:done --yes
   task-tracker list done
   task-tracker list pending
+  task-tracker board pending
   task-tracker delete 1
+  task-tracker serve --addr :8080 --token "$TOKEN"
 
 Notes:
-- Tasks are stored in tasks.json file
-- Default list filter is 'all'
+- Tasks are stored in tasks.json file unless configured otherwise
+- Default list filter is 'all' unless configured otherwise
 - 'pending' filter shows both 'todo' and 'in-progress' tasks
+- <ids> accepts IDs, lists (1,4,9), ranges (3-7) or --where status:<filter>
+  and --where title:<text>; add --yes to skip the confirmation for many tasks
+
+Exit codes:
+  0  success
+  1  unexpected failure
+  2  usage: unknown command, missing or malformed arguments, invalid config file
+  3  invalid input, such as an empty title or unknown status
+  4  the task does not exist
+  5  conflict: the change clashes with the stored task
+  6  storage: the tasks file cannot be read, parsed or written
 `
 	fmt.Print(helpText)
+	fmt.Print(c.aliasHelp())
+	fmt.Print(c.pluginHelp())
 	return nil
 }
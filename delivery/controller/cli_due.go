@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+)
+
+// dueUsage describes the due command
+const dueUsage = "Usage: due <id> <date|+duration|today|tomorrow|clear>"
+
+// handleDue processes the due command
+func (c *CLIController) handleDue(args []string) error {
+	if len(args) != 2 {
+		return usageErrorf("due command requires ID and date. %s", dueUsage)
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return usageErrorf("invalid task ID: %s", args[0])
+	}
+
+	var due *time.Time
+	if args[1] != "clear" {
+		parsed, err := c.parseDue(args[1], time.Now())
+		if err != nil {
+			return err
+		}
+		due = &parsed
+	}
+
+	task, err := c.taskManager.SetDue(id, due)
+	if err != nil {
+		return fmt.Errorf("failed to set due date: %w", err)
+	}
+
+	if due == nil {
+		fmt.Printf("Due date cleared\n")
+	} else {
+		fmt.Printf("Due date set\n")
+	}
+	c.printTask(task)
+	return nil
+}
+
+// parseDue parses a due date given as a date, a date and time, a duration
+// from now such as +2h or +3d, or today/tomorrow; a date without a time
+// means the end of that day
+func (c *CLIController) parseDue(value string, now time.Time) (time.Time, error) {
+	endOfDay := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 0, t.Location())
+	}
+
+	switch value {
+	case "today":
+		return endOfDay(now), nil
+	case "tomorrow":
+		return endOfDay(now.AddDate(0, 0, 1)), nil
+	}
+
+	if offset, ok := strings.CutPrefix(value, "+"); ok {
+		if due, ok := addOffset(now, offset, 1); ok {
+			return due, nil
+		}
+		return time.Time{}, usageErrorf("invalid due offset: %s. Use e.g. +30m, +2h, +3d or +1w", value)
+	}
+
+	if due, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return endOfDay(due), nil
+	}
+	for _, layout := range []string{c.config.DateFormat, time.RFC3339, "2006-01-02 15:04", "2006-01-02T15:04"} {
+		if due, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return due, nil
+		}
+	}
+	return time.Time{}, usageErrorf("invalid due date: %s. %s", value, dueUsage)
+}
+
+// addOffset moves now by an offset of days (3d), weeks (1w) or a Go
+// duration (2h), forwards for sign 1 and backwards for sign -1
+func addOffset(now time.Time, offset string, sign int) (time.Time, bool) {
+	if days, ok := strings.CutSuffix(offset, "d"); ok {
+		n, err := strconv.Atoi(days)
+		return now.AddDate(0, 0, sign*n), err == nil && n >= 0
+	}
+	if weeks, ok := strings.CutSuffix(offset, "w"); ok {
+		n, err := strconv.Atoi(weeks)
+		return now.AddDate(0, 0, sign*7*n), err == nil && n >= 0
+	}
+	d, err := time.ParseDuration(offset)
+	return now.Add(time.Duration(sign) * d), err == nil && d >= 0
+}
+
+// formatDue renders a due date, flagging it when the task is overdue
+func (c *CLIController) formatDue(task *entity.Task) string {
+	due := task.Due.Local().Format(c.config.DateFormat)
+	if !task.IsOverdue(time.Now()) {
+		return due
+	}
+	if c.color {
+		return due + " \x1b[31m(overdue)" + ansiReset
+	}
+	return due + " (overdue)"
+}
@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+	"github.com/Illuminateee/task-tracker.git/manager"
+	"github.com/Illuminateee/task-tracker.git/repository"
+)
+
+// mergeDriverName is the name the git merge driver is registered under
+const mergeDriverName = "task-tracker"
+
+// mergeUsage describes the merge command
+const mergeUsage = "Usage: merge [--output <file>] [--report <file>] <base> <ours> <theirs> | merge --install [pattern]"
+
+// handleMerge processes the merge command; like a git merge driver it writes
+// the result over ours unless --output is given
+func (c *CLIController) handleMerge(args []string) error {
+	flags := flag.NewFlagSet("merge", flag.ContinueOnError)
+	output := flags.String("output", "", "write the merged tasks here instead of over ours")
+	report := flags.String("report", "", "write conflicts here instead of to stderr")
+	install := flags.Bool("install", false, "register merge as the git merge driver for the tasks file")
+	if err := flags.Parse(args); err != nil {
+		return usageErrorf("invalid merge arguments. %s", mergeUsage)
+	}
+
+	if *install {
+		return c.installMergeDriver(flags.Args())
+	}
+	if flags.NArg() != 3 {
+		return usageErrorf("merge requires three task files. %s", mergeUsage)
+	}
+	base, ours, theirs := flags.Arg(0), flags.Arg(1), flags.Arg(2)
+	if *output == "" {
+		*output = ours
+	}
+
+	result, err := manager.MergeFiles(base, ours, theirs, *output, repository.FileOptions{Secret: c.openSecret})
+	if err != nil {
+		return fmt.Errorf("failed to merge tasks: %w", err)
+	}
+
+	fmt.Printf("Merged %d tasks into %s\n", len(result.Tasks), *output)
+	for _, oldID := range slices.Sorted(maps.Keys(result.Renumbered)) {
+		fmt.Printf("Task %d added in theirs renumbered to %d\n", oldID, result.Renumbered[oldID])
+	}
+
+	if len(result.Conflicts) == 0 {
+		if *report != "" {
+			// Drop the report of an earlier, conflicted attempt
+			os.Remove(*report)
+		}
+		return nil
+	}
+
+	where := "above"
+	if *report != "" {
+		if err := os.WriteFile(*report, []byte(result.Report()), 0644); err != nil {
+			return fmt.Errorf("failed to write conflict report: %w", err)
+		}
+		where = *report
+	} else {
+		fmt.Fprint(os.Stderr, result.Report())
+	}
+	return fmt.Errorf("%w: %d merge conflict(s); see %s", entity.ErrConflict, len(result.Conflicts), where)
+}
+
+// installMergeDriver registers merge as a git merge driver in the current
+// repository and assigns it to pattern in .gitattributes
+func (c *CLIController) installMergeDriver(args []string) error {
+	if len(args) > 1 {
+		return usageErrorf("merge --install takes at most one pattern. %s", mergeUsage)
+	}
+	pattern := filepath.Base(c.dataFilePath)
+	if len(args) == 1 {
+		pattern = args[0]
+	}
+
+	topLevel, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return usageErrorf("merge --install must be run inside a git repository")
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the task-tracker executable: %w", err)
+	}
+
+	// %O, %A and %B are the base, ours and theirs files; %P is the path being merged
+	driver := fmt.Sprintf("%s merge --report %%P.conflicts %%O %%A %%B", shellQuote(executable))
+	settings := [][2]string{
+		{"merge." + mergeDriverName + ".name", "task-tracker three-way task merge"},
+		{"merge." + mergeDriverName + ".driver", driver},
+	}
+	for _, setting := range settings {
+		if out, err := exec.Command("git", "config", setting[0], setting[1]).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to set git config %s: %v: %s", setting[0], err, bytes.TrimSpace(out))
+		}
+	}
+
+	attributesPath := filepath.Join(string(bytes.TrimSpace(topLevel)), ".gitattributes")
+	added, err := addGitAttribute(attributesPath, pattern+" merge="+mergeDriverName)
+	if err != nil {
+		return fmt.Errorf("failed to update .gitattributes: %w", err)
+	}
+
+	fmt.Printf("Registered git merge driver %q\n", mergeDriverName)
+	if added {
+		fmt.Printf("Added '%s merge=%s' to %s\n", pattern, mergeDriverName, attributesPath)
+	}
+	return nil
+}
+
+// addGitAttribute appends line to the attributes file unless it is already there
+func addGitAttribute(path, line string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+	for _, existing := range strings.Split(string(data), "\n") {
+		if strings.Join(strings.Fields(existing), " ") == line {
+			return false, nil
+		}
+	}
+
+	if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		line = "\n" + line
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+	if _, err := file.WriteString(line + "\n"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// shellQuote quotes s for use in a POSIX shell command line
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=+:,@%") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// pluginPrefix starts the executable name of every external subcommand
+const pluginPrefix = "task-tracker-"
+
+// pluginDrainTimeout bounds how long requests sent just before a plugin exits are still served
+const pluginDrainTimeout = time.Second
+
+// Environment describing the store to a plugin
+const (
+	pluginDataEnv   = "TASK_TRACKER_DATA"
+	pluginConfigEnv = "TASK_TRACKER_CONFIG"
+	pluginBinEnv    = "TASK_TRACKER_BIN"
+	pluginNameEnv   = "TASK_TRACKER_PLUGIN"
+	// The plugin writes JSON-RPC requests to one descriptor and reads the
+	// responses from the other
+	pluginRPCWriteEnv = "TASK_TRACKER_RPC_WRITE_FD"
+	pluginRPCReadEnv  = "TASK_TRACKER_RPC_READ_FD"
+)
+
+// findPlugin returns the executable implementing an unknown command, looking
+// in the plugins directory before PATH
+func (c *CLIController) findPlugin(command string) (string, bool) {
+	if command == "" || strings.ContainsAny(command, `/\`) || strings.HasPrefix(command, "-") || strings.HasPrefix(command, "_") {
+		return "", false
+	}
+
+	name := pluginPrefix + command
+	if c.config.PluginsDir != "" {
+		if path, err := exec.LookPath(filepath.Join(c.config.PluginsDir, name)); err == nil {
+			return path, true
+		}
+	}
+	if path, err := exec.LookPath(name); err == nil {
+		return path, true
+	}
+	return "", false
+}
+
+// runPlugin runs an external subcommand with the remaining arguments, serving
+// JSON-RPC to it over a pair of pipes for as long as it runs
+func (c *CLIController) runPlugin(path, command string, args []string) error {
+	if err := c.unlockStorage(); err != nil {
+		return err
+	}
+
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		pluginDataEnv+"="+c.dataFilePath,
+		pluginNameEnv+"="+command,
+	)
+	if c.config.Path != "" {
+		cmd.Env = append(cmd.Env, pluginConfigEnv+"="+c.config.Path)
+	}
+	if executable, err := os.Executable(); err == nil {
+		cmd.Env = append(cmd.Env, pluginBinEnv+"="+executable)
+	}
+
+	// Extra descriptors are not inherited on Windows; plugins there call the CLI instead
+	if runtime.GOOS == "windows" {
+		return pluginResult(command, cmd.Run())
+	}
+
+	requestsRead, requestsWrite, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("failed to create plugin pipe: %w", err)
+	}
+	defer requestsRead.Close()
+	responsesRead, responsesWrite, err := os.Pipe()
+	if err != nil {
+		requestsWrite.Close()
+		return fmt.Errorf("failed to create plugin pipe: %w", err)
+	}
+	defer responsesWrite.Close()
+
+	// ExtraFiles[i] becomes descriptor 3+i in the plugin
+	cmd.ExtraFiles = []*os.File{responsesRead, requestsWrite}
+	cmd.Env = append(cmd.Env, pluginRPCReadEnv+"=3", pluginRPCWriteEnv+"=4")
+
+	err = cmd.Start()
+	// The plugin holds its own copies now; closing ours lets its exit end the session
+	responsesRead.Close()
+	requestsWrite.Close()
+	if err != nil {
+		return fmt.Errorf("failed to start plugin %s: %w", command, err)
+	}
+
+	served := make(chan struct{})
+	go func() {
+		defer close(served)
+		NewRPCController(c.taskManager, c.dataFilePath).Serve(context.Background(), requestsRead, responsesWrite)
+	}()
+
+	err = cmd.Wait()
+	select {
+	case <-served:
+	case <-time.After(pluginDrainTimeout):
+		// A process the plugin started still holds the request pipe open
+		requestsRead.Close()
+		<-served
+	}
+	return pluginResult(command, err)
+}
+
+// pluginResult turns the outcome of a plugin run into the command's error
+func pluginResult(command string, err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return &PluginExitError{Plugin: command, Code: exitErr.ExitCode()}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run plugin %s: %w", command, err)
+	}
+	return nil
+}
+
+// pluginNames lists the plugins found in the plugins directory and on PATH
+func (c *CLIController) pluginNames() []string {
+	dirs := filepath.SplitList(os.Getenv("PATH"))
+	if c.config.PluginsDir != "" {
+		dirs = append([]string{c.config.PluginsDir}, dirs...)
+	}
+
+	var names []string
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			command, ok := strings.CutPrefix(entry.Name(), pluginPrefix)
+			if !ok || slices.Contains(names, command) || slices.Contains(commandNames, command) {
+				continue
+			}
+			if path, found := c.findPlugin(command); found && filepath.Dir(path) == filepath.Clean(dir) {
+				names = append(names, command)
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// pluginHelp lists the installed plugins for the help text
+func (c *CLIController) pluginHelp() string {
+	names := c.pluginNames()
+	if len(names) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\nPlugins:\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "  %s\n", name)
+	}
+	return b.String()
+}
@@ -0,0 +1,191 @@
+package controller
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+	"github.com/Illuminateee/task-tracker.git/usecase"
+)
+
+// reportUsage describes the report command
+const reportUsage = "Usage: report standup|week [--since <time>] [--until <time>] [--format text|markdown]"
+
+// reportTimeLayout renders the report window, independent of date_format so reports read the same in chat
+const reportTimeLayout = "Mon 2006-01-02 15:04"
+
+// markdownEscaper escapes the characters in task titles that markdown would format
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`)
+
+// reportSection is a titled list of tasks in a report
+type reportSection struct {
+	title string
+	tasks []*entity.Task
+}
+
+// handleReport processes the report command
+func (c *CLIController) handleReport(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("report command requires a kind. %s", reportUsage)
+	}
+	kind := args[0]
+
+	now := time.Now()
+	var defaultSince time.Time
+	switch kind {
+	case "standup":
+		defaultSince = previousWorkday(now)
+	case "week":
+		defaultSince = usecase.StartOfWeek(now)
+	default:
+		return usageErrorf("unknown report: %s. %s", kind, reportUsage)
+	}
+
+	flags := flag.NewFlagSet("report "+kind, flag.ContinueOnError)
+	sinceValue := flags.String("since", "", "start of the window (default: the previous workday for standup, Monday for week)")
+	untilValue := flags.String("until", "now", "end of the window")
+	format := flags.String("format", "text", "text or markdown")
+	if err := flags.Parse(args[1:]); err != nil || flags.NArg() > 0 {
+		return usageErrorf("invalid report arguments. %s", reportUsage)
+	}
+	if *format != "text" && *format != "markdown" {
+		return usageErrorf("invalid report format: %s. %s", *format, reportUsage)
+	}
+
+	since := defaultSince
+	if *sinceValue != "" {
+		parsed, err := c.parseReportTime(*sinceValue, now)
+		if err != nil {
+			return err
+		}
+		since = parsed
+	}
+	until, err := c.parseReportTime(*untilValue, now)
+	if err != nil {
+		return err
+	}
+
+	report, err := c.taskManager.Report(since, until)
+	if err != nil {
+		return fmt.Errorf("failed to build report: %w", err)
+	}
+
+	title := "Standup"
+	sections := []reportSection{
+		{"Done", report.Completed},
+		{"In progress", report.InProgress},
+		{"Overdue", report.Overdue},
+	}
+	if kind == "week" {
+		title = "Week"
+		sections = []reportSection{
+			{"Done", report.Completed},
+			{"Added", report.Added},
+			{"In progress", report.InProgress},
+			{"Overdue", report.Overdue},
+		}
+	}
+	title = fmt.Sprintf("%s, %s to %s", title, report.Since.Format(reportTimeLayout), report.Until.Format(reportTimeLayout))
+
+	if *format == "markdown" {
+		fmt.Print(renderMarkdownReport(title, sections, report.Until))
+	} else {
+		fmt.Print(renderTextReport(title, sections, report.Until))
+	}
+	return nil
+}
+
+// parseReportTime parses a report window boundary given as now, today,
+// yesterday, an offset into the past such as -2d or -1w, or a date; a date
+// without a time means the start of that day
+func (c *CLIController) parseReportTime(value string, now time.Time) (time.Time, error) {
+	startOfDay := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	}
+
+	switch value {
+	case "now":
+		return now, nil
+	case "today":
+		return startOfDay(now), nil
+	case "yesterday":
+		return startOfDay(now.AddDate(0, 0, -1)), nil
+	}
+
+	if offset, ok := strings.CutPrefix(value, "-"); ok {
+		if t, ok := addOffset(now, offset, -1); ok {
+			return t, nil
+		}
+		return time.Time{}, usageErrorf("invalid report offset: %s. Use e.g. -12h, -2d or -1w", value)
+	}
+
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return startOfDay(t), nil
+	}
+	for _, layout := range []string{c.config.DateFormat, time.RFC3339, "2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, usageErrorf("invalid report time: %s. %s", value, reportUsage)
+}
+
+// previousWorkday returns the start of the last weekday before now's day, so
+// a Monday standup covers Friday
+func previousWorkday(now time.Time) time.Time {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -1)
+	for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// renderTextReport renders a report as plain text
+func renderTextReport(title string, sections []reportSection, until time.Time) string {
+	var b strings.Builder
+	b.WriteString(title + "\n")
+	for _, section := range sections {
+		fmt.Fprintf(&b, "\n%s (%d)\n", section.title, len(section.tasks))
+		if len(section.tasks) == 0 {
+			b.WriteString("  none\n")
+		}
+		for _, task := range section.tasks {
+			fmt.Fprintf(&b, "  #%d %s", task.ID, task.Title)
+			if due := reportDue(task, until); due != "" {
+				fmt.Fprintf(&b, " (due %s)", due)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// renderMarkdownReport renders a report as markdown ready to paste into chat
+func renderMarkdownReport(title string, sections []reportSection, until time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### %s\n", title)
+	for _, section := range sections {
+		fmt.Fprintf(&b, "\n**%s (%d)**\n", section.title, len(section.tasks))
+		if len(section.tasks) == 0 {
+			b.WriteString("- _none_\n")
+		}
+		for _, task := range section.tasks {
+			fmt.Fprintf(&b, "- %s (#%d", markdownEscaper.Replace(task.Title), task.ID)
+			if due := reportDue(task, until); due != "" {
+				fmt.Fprintf(&b, ", due %s", due)
+			}
+			b.WriteString(")\n")
+		}
+	}
+	return b.String()
+}
+
+// reportDue returns the due date of a task overdue at until, or "" otherwise
+func reportDue(task *entity.Task, until time.Time) string {
+	if !task.IsOverdue(until) {
+		return ""
+	}
+	return task.Due.Local().Format(time.DateOnly)
+}
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+	"github.com/Illuminateee/task-tracker.git/usecase"
+)
+
+// statsUsage describes the stats command
+const statsUsage = "Usage: stats [--weeks <n>] [--oldest <n>]"
+
+// handleStats processes the stats command
+func (c *CLIController) handleStats(args []string) error {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	weeks := flags.Int("weeks", 8, "number of weeks to report, including this one")
+	oldest := flags.Int("oldest", 5, "number of oldest open tasks to list")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 0 {
+		return usageErrorf("invalid stats arguments. %s", statsUsage)
+	}
+	if *weeks < 1 || *oldest < 0 {
+		return usageErrorf("--weeks must be positive and --oldest not negative. %s", statsUsage)
+	}
+
+	stats, err := c.taskManager.Stats(*weeks, *oldest)
+	if err != nil {
+		return fmt.Errorf("failed to compute stats: %w", err)
+	}
+
+	fmt.Printf("Tasks: %d (", stats.Total)
+	for i, status := range []entity.TaskStatus{entity.TaskStatusToDo, entity.TaskStatusInProgress, entity.TaskStatusDone} {
+		if i > 0 {
+			fmt.Print(", ")
+		}
+		fmt.Printf("%s %d", c.formatStatus(status), stats.ByStatus[status])
+	}
+	fmt.Print(")\n\n")
+
+	fmt.Printf("%-12s %8s %10s\n", "Week of", "Created", "Completed")
+	for _, week := range stats.Weeks {
+		fmt.Printf("%-12s %8d %10d\n", week.Start.Format(time.DateOnly), week.Created, week.Completed)
+	}
+	fmt.Println()
+
+	printDurationStats("Cycle time (in-progress to done)", stats.CycleTime)
+	printDurationStats("Lead time (created to done)", stats.LeadTime)
+
+	if len(stats.Oldest) > 0 {
+		fmt.Println("\nOldest open tasks:")
+		now := time.Now()
+		for _, task := range stats.Oldest {
+			fmt.Printf("  %4d  %-8s %-11s %s\n", task.ID, formatDuration(now.Sub(task.CreatedAt)), c.formatStatus(task.Status), task.Title)
+		}
+	}
+	return nil
+}
+
+// printDurationStats prints one line summarizing a flow metric
+func printDurationStats(name string, stats usecase.DurationStats) {
+	if stats.Count == 0 {
+		fmt.Printf("%s: no tasks completed\n", name)
+		return
+	}
+	fmt.Printf("%s, %d task(s): average %s, median %s, 85th percentile %s, 95th percentile %s\n", name, stats.Count,
+		formatDuration(stats.Average), formatDuration(stats.Median), formatDuration(stats.P85), formatDuration(stats.P95))
+}
+
+// formatDuration renders a duration in days and hours, or hours and minutes under a day
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return "<1m"
+	}
+
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	var parts []string
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%dd", days))
+	}
+	if hours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+	}
+	if minutes > 0 && days == 0 {
+		parts = append(parts, fmt.Sprintf("%dm", minutes))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,282 @@
+package controller
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Illuminateee/task-tracker.git/delivery/terminal"
+	"github.com/Illuminateee/task-tracker.git/entity"
+	"github.com/Illuminateee/task-tracker.git/repository"
+)
+
+// storageUsage describes the storage subcommands
+const storageUsage = "Usage: storage [info] | storage convert --to json|binary | storage encrypt|rekey [--key-file <path>] | storage decrypt"
+
+// Environment variables supplying passphrases without a prompt
+const (
+	passphraseEnv    = "TASK_TRACKER_PASSPHRASE"
+	newPassphraseEnv = "TASK_TRACKER_NEW_PASSPHRASE"
+)
+
+// handleStorage processes the storage command
+func (c *CLIController) handleStorage(args []string) error {
+	if len(args) == 0 {
+		return c.handleStorageInfo(nil)
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "info":
+		return c.handleStorageInfo(args[1:])
+	case "convert":
+		return c.handleStorageConvert(args[1:])
+	case "encrypt", "rekey":
+		return c.handleStorageSeal(strings.ToLower(args[0]), args[1:])
+	case "decrypt":
+		return c.handleStorageDecrypt(args[1:])
+	default:
+		return usageErrorf("unknown storage command: %s. %s", args[0], storageUsage)
+	}
+}
+
+// handleStorageInfo prints the location, format and size of the tasks file
+func (c *CLIController) handleStorageInfo(args []string) error {
+	if len(args) > 0 {
+		return usageErrorf("storage info takes no arguments. %s", storageUsage)
+	}
+
+	format, err := c.taskManager.StorageFormat()
+	if err != nil {
+		return fmt.Errorf("failed to read storage: %w", err)
+	}
+	encryption, err := c.taskManager.StorageEncryption()
+	if err != nil {
+		return fmt.Errorf("failed to read storage: %w", err)
+	}
+	page, err := c.taskManager.ListTasksPage("all", 1, 1)
+	if err != nil {
+		return fmt.Errorf("failed to read storage: %w", err)
+	}
+
+	fmt.Printf("Data file: %s\n", c.dataFilePath)
+	fmt.Printf("Format: %s\n", format)
+	fmt.Printf("Encryption: %s\n", describeEncryption(encryption))
+	fmt.Printf("Size: %s\n", c.dataFileSize())
+	fmt.Printf("Tasks: %d\n", page.Total)
+	return nil
+}
+
+// handleStorageSeal encrypts a plaintext tasks file or changes the key of an
+// encrypted one
+func (c *CLIController) handleStorageSeal(command string, args []string) error {
+	flags := flag.NewFlagSet("storage "+command, flag.ContinueOnError)
+	keyFile := flags.String("key-file", "", "seal with the contents of this file instead of a passphrase")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 0 {
+		return usageErrorf("invalid storage %s arguments. Usage: storage %s [--key-file <path>]", command, command)
+	}
+
+	// Open the current file first so a wrong key fails before asking for a new one
+	encryption, err := c.taskManager.StorageEncryption()
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	if command == "encrypt" && encryption.Encrypted {
+		return fmt.Errorf("%w: tasks file is already encrypted. Use storage rekey to change its key", entity.ErrInvalidInput)
+	}
+	if command == "rekey" && !encryption.Encrypted {
+		return fmt.Errorf("%w: tasks file is not encrypted. Use storage encrypt first", entity.ErrInvalidInput)
+	}
+
+	var secret repository.Secret
+	if command == "encrypt" {
+		if *keyFile == "" {
+			*keyFile = c.config.KeyFile
+		}
+		secret, err = newSecret(*keyFile, passphraseEnv)
+	} else {
+		secret, err = newSecret(*keyFile, newPassphraseEnv)
+	}
+	if err != nil {
+		return err
+	}
+
+	if command == "encrypt" {
+		err = c.taskManager.EncryptStorage(secret)
+	} else {
+		err = c.taskManager.RekeyStorage(secret)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to %s storage: %w", command, err)
+	}
+
+	verb := "Encrypted"
+	if command == "rekey" {
+		verb = "Re-encrypted"
+	}
+	fmt.Printf("%s %s with a %s\n", verb, c.dataFilePath, secret.Kind)
+	if secret.Kind == repository.SecretKeyFile && *keyFile != c.config.KeyFile {
+		fmt.Printf("Set key_file = %s in the config file to open it\n", *keyFile)
+	}
+	if secret.Kind == repository.SecretPassphrase && c.config.KeyFile != "" {
+		fmt.Println("Remove key_file from the config file to be asked for the passphrase")
+	}
+	return nil
+}
+
+// handleStorageDecrypt rewrites an encrypted tasks file as plaintext
+func (c *CLIController) handleStorageDecrypt(args []string) error {
+	if len(args) > 0 {
+		return usageErrorf("storage decrypt takes no arguments. Usage: storage decrypt")
+	}
+
+	if err := c.unlockStorage(); err != nil {
+		return err
+	}
+	if err := c.taskManager.DecryptStorage(); err != nil {
+		return fmt.Errorf("failed to decrypt storage: %w", err)
+	}
+
+	fmt.Printf("Decrypted %s\n", c.dataFilePath)
+	return nil
+}
+
+// unlockStorage opens the tasks file, asking for the passphrase of an
+// encrypted file before the terminal or stdin is taken over by another mode
+func (c *CLIController) unlockStorage() error {
+	if _, err := c.taskManager.StorageEncryption(); err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	return nil
+}
+
+// openSecret returns the secret for opening an encrypted tasks file: the
+// key_file setting, then TASK_TRACKER_PASSPHRASE, then a terminal prompt
+func (c *CLIController) openSecret() (repository.Secret, error) {
+	if c.config.KeyFile != "" {
+		return readKeyFile(c.config.KeyFile)
+	}
+	if passphrase := os.Getenv(passphraseEnv); passphrase != "" {
+		return repository.Secret{Kind: repository.SecretPassphrase, Value: []byte(passphrase)}, nil
+	}
+
+	passphrase, err := promptPassphrase("Passphrase: ")
+	if err != nil {
+		return repository.Secret{}, err
+	}
+	return repository.Secret{Kind: repository.SecretPassphrase, Value: passphrase}, nil
+}
+
+// newSecret returns the secret to seal the tasks file with: keyFile when set,
+// then the passphrase in the env variable, then a passphrase entered twice
+func newSecret(keyFile, env string) (repository.Secret, error) {
+	if keyFile != "" {
+		return readKeyFile(keyFile)
+	}
+	if passphrase := os.Getenv(env); passphrase != "" {
+		return repository.Secret{Kind: repository.SecretPassphrase, Value: []byte(passphrase)}, nil
+	}
+
+	passphrase, err := promptPassphrase("New passphrase: ")
+	if err != nil {
+		return repository.Secret{}, err
+	}
+	if len(passphrase) == 0 {
+		return repository.Secret{}, usageErrorf("passphrase cannot be empty")
+	}
+	confirmation, err := promptPassphrase("Repeat passphrase: ")
+	if err != nil {
+		return repository.Secret{}, err
+	}
+	if string(confirmation) != string(passphrase) {
+		return repository.Secret{}, usageErrorf("passphrases do not match")
+	}
+	return repository.Secret{Kind: repository.SecretPassphrase, Value: passphrase}, nil
+}
+
+// readKeyFile loads key material from path
+func readKeyFile(path string) (repository.Secret, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return repository.Secret{}, fmt.Errorf("failed to read key file: %w", err)
+	}
+	return repository.Secret{Kind: repository.SecretKeyFile, Value: data}, nil
+}
+
+// promptPassphrase reads a passphrase from the terminal without echoing it
+func promptPassphrase(label string) ([]byte, error) {
+	if !terminal.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, fmt.Errorf("a passphrase is required; set %s or key_file, or run interactively", passphraseEnv)
+	}
+
+	fmt.Fprint(os.Stderr, label)
+	passphrase, err := terminal.ReadPassword(os.Stdin)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return passphrase, nil
+}
+
+// describeEncryption formats encryption details for display
+func describeEncryption(info repository.EncryptionInfo) string {
+	switch {
+	case !info.Encrypted:
+		return "none"
+	case info.Kind == repository.SecretPassphrase:
+		return fmt.Sprintf("AES-256-GCM, passphrase (PBKDF2-SHA256, %d iterations)", info.Iterations)
+	default:
+		return fmt.Sprintf("AES-256-GCM, %s (HKDF-SHA256)", info.Kind)
+	}
+}
+
+// handleStorageConvert rewrites the tasks file in another format
+func (c *CLIController) handleStorageConvert(args []string) error {
+	flags := flag.NewFlagSet("storage convert", flag.ContinueOnError)
+	to := flags.String("to", "", "target format: json or binary")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 0 || *to == "" {
+		return usageErrorf("storage convert requires a target format. Usage: storage convert --to json|binary")
+	}
+	target := strings.ToLower(*to)
+
+	from, err := c.taskManager.StorageFormat()
+	if err != nil {
+		return fmt.Errorf("failed to read storage: %w", err)
+	}
+	if string(from) == target {
+		fmt.Printf("Tasks are already stored as %s\n", target)
+		return nil
+	}
+
+	before := c.dataFileSize()
+	if err := c.taskManager.ConvertStorage(target); err != nil {
+		return fmt.Errorf("failed to convert storage: %w", err)
+	}
+
+	fmt.Printf("Converted %s from %s to %s (%s -> %s)\n", c.dataFilePath, from, target, before, c.dataFileSize())
+	return nil
+}
+
+// dataFileSize returns the size of the tasks file for display
+func (c *CLIController) dataFileSize() string {
+	info, err := os.Stat(c.dataFilePath)
+	if err != nil {
+		return "0 B"
+	}
+	return formatBytes(info.Size())
+}
+
+// formatBytes formats a byte count with a binary unit
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+
+	"github.com/Illuminateee/task-tracker.git/repository"
+	"github.com/Illuminateee/task-tracker.git/usecase"
+)
+
+// syncTokenEnv holds the sync token when it is not in the config file
+const syncTokenEnv = "TASK_TRACKER_SYNC_TOKEN"
+
+// handleSync processes the sync command
+func (c *CLIController) handleSync(args []string) error {
+	flags := flag.NewFlagSet("sync", flag.ContinueOnError)
+	url := flags.String("url", c.config.SyncURL, "sync server URL")
+	token := flags.String("token", "", "sync server token")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 0 {
+		return usageErrorf("invalid sync arguments. Usage: sync [--url <url>] [--token <token>]")
+	}
+	if *url == "" {
+		return usageErrorf("no sync server configured. Set sync_url in the config file or pass --url")
+	}
+	if *token == "" {
+		*token = c.syncToken()
+	}
+	if err := c.unlockStorage(); err != nil {
+		return err
+	}
+
+	result, err := c.taskManager.Sync(newHTTPSyncExchange(*url, *token))
+	if err != nil {
+		return fmt.Errorf("failed to sync: %w", err)
+	}
+
+	fmt.Printf("Pushed %d change(s), pulled %d task(s)\n", result.Pushed, result.Pulled)
+	for _, oldID := range slices.Sorted(maps.Keys(result.Renumbered)) {
+		fmt.Printf("Task %d renumbered to %d\n", oldID, result.Renumbered[oldID])
+	}
+	return nil
+}
+
+// handleSyncServer processes the sync-server command
+func (c *CLIController) handleSyncServer(args []string) error {
+	flags := flag.NewFlagSet("sync-server", flag.ContinueOnError)
+	addr := flags.String("addr", "", "address to listen on (default :8081, or 127.0.0.1:8081 without a token)")
+	store := flags.String("store", "sync-store.json", "file holding the synced tasks")
+	token := flags.String("token", "", "token devices must send")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 0 {
+		return usageErrorf("invalid sync-server arguments. Usage: sync-server [--addr <host:port>] [--store <file>] [--token <token>]")
+	}
+	if *token == "" {
+		*token = c.syncToken()
+	}
+
+	// Without a token anyone who can reach the server could read and change every task
+	switch {
+	case *addr == "" && *token == "":
+		*addr = "127.0.0.1:8081"
+	case *addr == "":
+		*addr = ":8081"
+	case *token == "" && !isLoopbackAddr(*addr):
+		return usageErrorf("refusing to serve sync on %s without a token. Set sync_token, %s or --token, or listen on 127.0.0.1", *addr, syncTokenEnv)
+	}
+
+	syncServer := usecase.NewSyncServer(repository.NewFileSyncStore(*store))
+	server := &http.Server{
+		Addr:    *addr,
+		Handler: NewSyncController(syncServer, *token).Routes(),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+
+	fmt.Printf("Serving sync endpoint on %s (store: %s)\n", *addr, *store)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve: %w", err)
+	}
+	return nil
+}
+
+// isLoopbackAddr reports whether addr only accepts connections from this machine
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// syncToken returns the configured sync token, falling back to the environment
+func (c *CLIController) syncToken() string {
+	if c.config.SyncToken != "" {
+		return c.config.SyncToken
+	}
+	return os.Getenv(syncTokenEnv)
+}
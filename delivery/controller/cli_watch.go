@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/usecase"
+)
+
+// watchUsage describes the watch command
+const watchUsage = "Usage: watch [--interval <duration>] [--before <duration>] [--once] [--log <file>] [--exec <command>] [--webhook <url>]"
+
+// handleWatch processes the watch command, which also runs as daemon; it
+// scans the tasks on an interval and sends reminders until interrupted
+func (c *CLIController) handleWatch(args []string) error {
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	interval := flags.Duration("interval", time.Minute, "time between scans")
+	before := flags.Duration("before", c.config.RemindBefore, "how long before the due date to remind")
+	once := flags.Bool("once", false, "scan once and exit")
+	logPath := flags.String("log", c.config.RemindLog, "file to log reminders to instead of stdout")
+	command := flags.String("exec", c.config.RemindCommand, "command to run for every reminder")
+	webhook := flags.String("webhook", c.config.RemindWebhook, "URL to post every reminder to")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 0 {
+		return usageErrorf("invalid watch arguments. %s", watchUsage)
+	}
+	if *interval <= 0 || *before < 0 {
+		return usageErrorf("--interval must be positive and --before not negative. %s", watchUsage)
+	}
+	if err := c.unlockStorage(); err != nil {
+		return err
+	}
+
+	var logOutput io.Writer = os.Stdout
+	if *logPath != "" {
+		file, err := os.OpenFile(*logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open reminder log: %w", err)
+		}
+		defer file.Close()
+		logOutput = file
+	}
+
+	sinks := []usecase.ReminderSink{&logSink{w: logOutput, dateFormat: c.config.DateFormat}}
+	if *command != "" {
+		sinks = append(sinks, &commandSink{command: *command, dateFormat: c.config.DateFormat})
+	}
+	if *webhook != "" {
+		sinks = append(sinks, newWebhookSink(*webhook))
+	}
+
+	if *once {
+		if _, err := c.taskManager.SendReminders(sinks, time.Now(), *before); err != nil {
+			return fmt.Errorf("failed to send reminders: %w", err)
+		}
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	go c.retryWebhooks(ctx)
+
+	fmt.Printf("Watching %s for tasks due within %s, every %s\n", c.dataFilePath, *before, *interval)
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		// A failing sink must not stop the watcher; its reminders are retried on the next scan
+		if _, err := c.taskManager.SendReminders(sinks, time.Now(), *before); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to send reminders: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// webhooksUsage describes the webhooks command
+const webhooksUsage = "Usage: webhooks [list] | webhooks flush [--all] | webhooks drop"
+
+// webhookRetryInterval is how often long-running commands retry queued webhook deliveries
+const webhookRetryInterval = 10 * time.Second
+
+// webhookExitWait bounds how long a command waits for its webhook deliveries before exiting
+const webhookExitWait = 3 * time.Second
+
+// handleWebhooks processes the webhooks command
+func (c *CLIController) handleWebhooks(args []string) error {
+	if !c.taskManager.WebhooksEnabled() {
+		return usageErrorf("no webhooks configured. Set webhooks and webhook_secret in the config file")
+	}
+
+	subcommand := "list"
+	if len(args) > 0 {
+		subcommand, args = args[0], args[1:]
+	}
+
+	switch subcommand {
+	case "list":
+		if len(args) > 0 {
+			return usageErrorf("webhooks list takes no arguments. %s", webhooksUsage)
+		}
+		return c.handleWebhooksList()
+	case "flush":
+		return c.handleWebhooksFlush(args)
+	case "drop":
+		if len(args) > 0 {
+			return usageErrorf("webhooks drop takes no arguments. %s", webhooksUsage)
+		}
+		dropped, err := c.taskManager.DropFailedWebhooks()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Dropped %d failed deliveries\n", dropped)
+		return nil
+	default:
+		return usageErrorf("unknown webhooks command: %s. %s", subcommand, webhooksUsage)
+	}
+}
+
+// handleWebhooksList prints the deliveries waiting in the outbox
+func (c *CLIController) handleWebhooksList() error {
+	entries, err := c.taskManager.PendingWebhooks()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println("No pending webhook deliveries")
+		return nil
+	}
+
+	for _, entry := range entries {
+		state := "next attempt " + entry.NextAttempt.Local().Format(c.config.DateFormat)
+		if entry.Failed {
+			state = "failed"
+		}
+		fmt.Printf("%s  %-13s  %s  attempts: %d, %s\n", entry.CreatedAt.Local().Format(c.config.DateFormat), entry.Event, entry.URL, entry.Attempts, state)
+		if entry.LastError != "" {
+			fmt.Printf("    last error: %s\n", entry.LastError)
+		}
+	}
+	return nil
+}
+
+// handleWebhooksFlush delivers the queued events now
+func (c *CLIController) handleWebhooksFlush(args []string) error {
+	flags := flag.NewFlagSet("webhooks flush", flag.ContinueOnError)
+	all := flags.Bool("all", false, "also retry deliveries that are backing off or failed")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 0 {
+		return usageErrorf("invalid webhooks flush arguments. %s", webhooksUsage)
+	}
+
+	result, err := c.taskManager.FlushWebhooks(*all)
+	if result != nil {
+		fmt.Printf("Delivered %d, retrying %d, failed %d\n", result.Delivered, result.Retrying, result.Failed)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to deliver webhooks: %w", err)
+	}
+	return nil
+}
+
+// Close waits briefly for the webhook deliveries started by the commands run
+// so far; deliveries still running are left in the outbox for a later flush
+func (c *CLIController) Close() {
+	if !c.taskManager.WaitForWebhooks(webhookExitWait) {
+		c.warn(errors.New("webhook delivery is taking too long; it stays queued for webhooks flush"))
+	}
+}
+
+// retryWebhooks delivers queued webhook events until ctx is done, for
+// commands that keep running
+func (c *CLIController) retryWebhooks(ctx context.Context) {
+	if !c.taskManager.WebhooksEnabled() {
+		return
+	}
+
+	ticker := time.NewTicker(webhookRetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if _, err := c.taskManager.FlushWebhooks(false); err != nil {
+			c.warn(err)
+		}
+	}
+}
+
+// warn reports a failure that does not fail the command
+func (c *CLIController) warn(err error) {
+	c.warnings.Print(err)
+}
+
+// heldWarnings collects the warnings logged while the terminal is in raw
+// mode, where writing them to stderr would garble the screen
+type heldWarnings struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+// Write implements io.Writer for a log.Logger, which writes one message per call
+func (w *heldWarnings) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.messages = append(w.messages, strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
+
+// take returns the collected warnings and forgets them
+func (w *heldWarnings) take() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	messages := w.messages
+	w.messages = nil
+	return messages
+}
+
+// holdWarnings redirects logger to a new heldWarnings; the returned function
+// points it back and writes out the warnings that were not taken
+func holdWarnings(logger *log.Logger) (*heldWarnings, func()) {
+	held := &heldWarnings{}
+	previous := logger.Writer()
+	logger.SetOutput(held)
+	return held, func() {
+		logger.SetOutput(previous)
+		for _, message := range held.take() {
+			fmt.Fprintln(previous, message)
+		}
+	}
+}
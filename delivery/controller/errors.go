@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+)
+
+// ErrUsage is returned when a command is invoked with invalid arguments
+var ErrUsage = errors.New("usage error")
+
+// Error codes reported by ErrorCode
+const (
+	ErrorCodeUsage        = "usage"
+	ErrorCodeInvalidInput = "invalid_input"
+	ErrorCodeNotFound     = "not_found"
+	ErrorCodeConflict     = "conflict"
+	ErrorCodeStorage      = "storage"
+	ErrorCodeInternal     = "internal"
+)
+
+// UsageError reports invalid command arguments and matches ErrUsage
+type UsageError struct {
+	Message string
+}
+
+// Error implements the error interface
+func (e *UsageError) Error() string {
+	return e.Message
+}
+
+// Is allows errors.Is(err, ErrUsage) to match
+func (e *UsageError) Is(target error) bool {
+	return target == ErrUsage
+}
+
+// PluginExitError reports that an external subcommand exited with a non-zero
+// status; the plugin has already reported its own error
+type PluginExitError struct {
+	Plugin string
+	Code   int
+}
+
+// Error implements the error interface
+func (e *PluginExitError) Error() string {
+	return fmt.Sprintf("plugin %s exited with status %d", e.Plugin, e.Code)
+}
+
+// usageErrorf formats a UsageError
+func usageErrorf(format string, args ...any) error {
+	return &UsageError{Message: fmt.Sprintf(format, args...)}
+}
+
+// ErrorCode classifies an error returned by a controller into a stable code
+func ErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrUsage):
+		return ErrorCodeUsage
+	case errors.Is(err, entity.ErrInvalidInput):
+		return ErrorCodeInvalidInput
+	case errors.Is(err, entity.ErrTaskNotFound):
+		return ErrorCodeNotFound
+	case errors.Is(err, entity.ErrConflict):
+		return ErrorCodeConflict
+	case errors.Is(err, entity.ErrStorage):
+		return ErrorCodeStorage
+	default:
+		return ErrorCodeInternal
+	}
+}
@@ -0,0 +1,310 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+	"github.com/Illuminateee/task-tracker.git/manager"
+)
+
+// maxRequestBody bounds the size of a request body the API will read
+const maxRequestBody = 1 << 20
+
+// HTTPController exposes task operations over a JSON REST API
+type HTTPController struct {
+	taskManager *manager.TaskManager
+	// token, when set, must be sent by clients as a bearer token
+	token string
+	// mu serializes requests because the underlying store is a single file
+	mu sync.Mutex
+}
+
+// taskRequest is the request body for creating and updating tasks
+type taskRequest struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	// Version, when set, rejects the update if the task has changed since
+	Version *int `json:"version"`
+}
+
+// statusRequest is the request body for changing task status
+type statusRequest struct {
+	Status  string `json:"status"`
+	Version *int   `json:"version"`
+}
+
+// errorResponse is the response body for failed requests
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewHTTPController creates a new HTTP controller
+func NewHTTPController(taskManager *manager.TaskManager, token string) *HTTPController {
+	return &HTTPController{
+		taskManager: taskManager,
+		token:       token,
+	}
+}
+
+// Routes returns the HTTP handler serving the REST API
+func (h *HTTPController) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tasks", h.handleList)
+	mux.HandleFunc("POST /tasks", h.handleCreate)
+	mux.HandleFunc("GET /tasks/{id}", h.handleGet)
+	mux.HandleFunc("PATCH /tasks/{id}", h.handleUpdate)
+	mux.HandleFunc("DELETE /tasks/{id}", h.handleDelete)
+	mux.HandleFunc("POST /tasks/{id}/status", h.handleStatus)
+	return h.authorize(mux)
+}
+
+// authorize rejects requests without the bearer token when one is set
+func (h *HTTPController) authorize(next http.Handler) http.Handler {
+	if h.token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+			h.writeJSON(w, http.StatusUnauthorized, errorResponse{Error: "missing or invalid API token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleList serves GET /tasks with an optional ?status= filter
+func (h *HTTPController) handleList(w http.ResponseWriter, r *http.Request) {
+	filter := strings.ToLower(r.URL.Query().Get("status"))
+
+	h.mu.Lock()
+	tasks, err := h.taskManager.ListTasks(filter)
+	h.mu.Unlock()
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if tasks == nil {
+		tasks = []*entity.Task{}
+	}
+	h.writeJSON(w, http.StatusOK, tasks)
+}
+
+// handleCreate serves POST /tasks
+func (h *HTTPController) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var req taskRequest
+	if err := h.decodeBody(w, r, &req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	title, description := "", ""
+	if req.Title != nil {
+		title = *req.Title
+	}
+	if req.Description != nil {
+		description = *req.Description
+	}
+
+	h.mu.Lock()
+	task, err := h.taskManager.AddTask(title, description)
+	h.mu.Unlock()
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/tasks/%d", task.ID))
+	h.writeTask(w, http.StatusCreated, task)
+}
+
+// handleGet serves GET /tasks/{id}
+func (h *HTTPController) handleGet(w http.ResponseWriter, r *http.Request) {
+	id, err := h.parseID(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.mu.Lock()
+	task, err := h.taskManager.GetTask(id)
+	h.mu.Unlock()
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeTask(w, http.StatusOK, task)
+}
+
+// handleUpdate serves PATCH /tasks/{id}
+func (h *HTTPController) handleUpdate(w http.ResponseWriter, r *http.Request) {
+	id, err := h.parseID(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	var req taskRequest
+	if err := h.decodeBody(w, r, &req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	// Empty values leave the corresponding field unchanged
+	title, description := "", ""
+	if req.Title != nil {
+		title = *req.Title
+	}
+	if req.Description != nil {
+		description = *req.Description
+	}
+
+	version, err := h.expectedVersion(r, req.Version)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.mu.Lock()
+	task, err := h.taskManager.UpdateTaskAtVersion(id, version, title, description)
+	h.mu.Unlock()
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeTask(w, http.StatusOK, task)
+}
+
+// handleDelete serves DELETE /tasks/{id}
+func (h *HTTPController) handleDelete(w http.ResponseWriter, r *http.Request) {
+	id, err := h.parseID(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.mu.Lock()
+	err = h.taskManager.DeleteTask(id)
+	h.mu.Unlock()
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleStatus serves POST /tasks/{id}/status
+func (h *HTTPController) handleStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := h.parseID(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	var req statusRequest
+	if err := h.decodeBody(w, r, &req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	version, err := h.expectedVersion(r, req.Version)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.mu.Lock()
+	task, err := h.taskManager.UpdateTaskStatusAtVersion(id, version, req.Status)
+	h.mu.Unlock()
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeTask(w, http.StatusOK, task)
+}
+
+// parseID extracts the task ID from the request path
+func (h *HTTPController) parseID(r *http.Request) (int, error) {
+	raw := r.PathValue("id")
+	id, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid task ID: %s", entity.ErrInvalidInput, raw)
+	}
+	return id, nil
+}
+
+// expectedVersion returns the task version the client expects, taken from the
+// If-Match header or the request body; entity.AnyVersion when it sent neither
+func (h *HTTPController) expectedVersion(r *http.Request, bodyVersion *int) (int, error) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		if bodyVersion == nil {
+			return entity.AnyVersion, nil
+		}
+		if *bodyVersion < 0 {
+			return 0, fmt.Errorf("%w: invalid version: %d", entity.ErrInvalidInput, *bodyVersion)
+		}
+		return *bodyVersion, nil
+	}
+
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`))
+	if err != nil || version < 0 {
+		return 0, fmt.Errorf("%w: invalid If-Match header: %s", entity.ErrInvalidInput, ifMatch)
+	}
+	if bodyVersion != nil && *bodyVersion != version {
+		return 0, fmt.Errorf("%w: If-Match header and body version disagree", entity.ErrInvalidInput)
+	}
+	return version, nil
+}
+
+// decodeBody decodes a JSON request body of at most maxRequestBody bytes into v
+func (h *HTTPController) decodeBody(w http.ResponseWriter, r *http.Request, v any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("%w: invalid request body: %w", entity.ErrInvalidInput, err)
+	}
+	return nil
+}
+
+// writeJSON writes v as a JSON response with the given status code
+func (h *HTTPController) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeTask writes a single task with its version as the ETag
+func (h *HTTPController) writeTask(w http.ResponseWriter, status int, task *entity.Task) {
+	w.Header().Set("ETag", strconv.Quote(strconv.Itoa(task.Version)))
+	h.writeJSON(w, status, task)
+}
+
+// writeError maps an error to an HTTP status code and writes it as JSON
+func (h *HTTPController) writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var tooLarge *http.MaxBytesError
+	switch code := ErrorCode(err); {
+	case errors.As(err, &tooLarge):
+		status = http.StatusRequestEntityTooLarge
+	case code == ErrorCodeUsage, code == ErrorCodeInvalidInput:
+		status = http.StatusBadRequest
+	case code == ErrorCodeNotFound:
+		status = http.StatusNotFound
+	case code == ErrorCodeConflict:
+		status = http.StatusConflict
+	}
+	h.writeJSON(w, status, errorResponse{Error: err.Error()})
+}
@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Illuminateee/task-tracker.git/manager"
+	"github.com/Illuminateee/task-tracker.git/repository"
+)
+
+// newTestAPI serves the REST API over a copy of the tasks file content
+func newTestAPI(t *testing.T, content string) *httptest.Server {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tm := manager.NewTaskManager(path, repository.FileOptions{})
+	server := httptest.NewServer(NewHTTPController(tm, "").Routes())
+	t.Cleanup(server.Close)
+	return server
+}
+
+// sendJSON makes a request with a JSON body and optional If-Match header
+func sendJSON(t *testing.T, method, url, ifMatch, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp
+}
+
+func TestHTTPRejectsStaleVersion(t *testing.T) {
+	server := newTestAPI(t, `[{"id": 1, "title": "Legacy", "status": "todo"}]`)
+	task := server.URL + "/tasks/1"
+
+	// The legacy task has no stored version and is served as version 1
+	if resp := sendJSON(t, http.MethodPatch, task, `"0"`, `{"title": "Stale"}`); resp.StatusCode != http.StatusConflict {
+		t.Errorf("PATCH with If-Match 0: status %d, want 409", resp.StatusCode)
+	}
+
+	resp := sendJSON(t, http.MethodPatch, task, `"1"`, `{"title": "Fresh"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("PATCH with If-Match 1: status %d, want 200", resp.StatusCode)
+	}
+	if etag := resp.Header.Get("ETag"); etag != `"2"` {
+		t.Errorf("ETag after update = %s, want \"2\"", etag)
+	}
+
+	if resp := sendJSON(t, http.MethodPatch, task, `"1"`, `{"title": "Stale"}`); resp.StatusCode != http.StatusConflict {
+		t.Errorf("PATCH with stale If-Match: status %d, want 409", resp.StatusCode)
+	}
+	if resp := sendJSON(t, http.MethodPost, task+"/status", "", `{"status": "done", "version": 1}`); resp.StatusCode != http.StatusConflict {
+		t.Errorf("status change with stale body version: status %d, want 409", resp.StatusCode)
+	}
+	if resp := sendJSON(t, http.MethodPost, task+"/status", "", `{"status": "done", "version": -1}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status change with negative version: status %d, want 400", resp.StatusCode)
+	}
+	if resp := sendJSON(t, http.MethodPost, task+"/status", "", `{"status": "done"}`); resp.StatusCode != http.StatusOK {
+		t.Errorf("status change without a version: status %d, want 200", resp.StatusCode)
+	}
+}
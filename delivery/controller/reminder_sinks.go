@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+)
+
+// reminderTimeout bounds how long a command or webhook may take to accept a reminder
+const reminderTimeout = 30 * time.Second
+
+// logSink writes reminders as lines of text, to stdout or a log file
+type logSink struct {
+	w          io.Writer
+	dateFormat string
+}
+
+// Name implements usecase.ReminderSink
+func (s *logSink) Name() string {
+	return "log"
+}
+
+// Send implements usecase.ReminderSink
+func (s *logSink) Send(reminder entity.Reminder) error {
+	_, err := fmt.Fprintf(s.w, "%s %s\n", time.Now().Format(s.dateFormat), reminderMessage(reminder, s.dateFormat))
+	return err
+}
+
+// commandSink runs a shell command for every reminder, passing the reminder
+// as JSON on stdin and its main fields in the environment
+type commandSink struct {
+	command    string
+	dateFormat string
+}
+
+// Name implements usecase.ReminderSink
+func (s *commandSink) Name() string {
+	return "command"
+}
+
+// Send implements usecase.ReminderSink
+func (s *commandSink) Send(reminder entity.Reminder) error {
+	payload, err := json.Marshal(reminder)
+	if err != nil {
+		return fmt.Errorf("failed to encode reminder: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), reminderTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", s.command)
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", s.command)
+	}
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"TASK_ID="+strconv.Itoa(reminder.Task.ID),
+		"TASK_TITLE="+reminder.Task.Title,
+		"TASK_DUE="+reminder.Task.Due.Format(time.RFC3339),
+		"TASK_REMINDER="+string(reminder.Kind),
+		"TASK_MESSAGE="+reminderMessage(reminder, s.dateFormat),
+	)
+	return cmd.Run()
+}
+
+// webhookSink posts every reminder as JSON to a URL
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// newWebhookSink creates a webhook sink posting to url
+func newWebhookSink(url string) *webhookSink {
+	return &webhookSink{
+		url:    url,
+		client: &http.Client{Timeout: reminderTimeout},
+	}
+}
+
+// Name implements usecase.ReminderSink
+func (s *webhookSink) Name() string {
+	return "webhook"
+}
+
+// Send implements usecase.ReminderSink
+func (s *webhookSink) Send(reminder entity.Reminder) error {
+	payload, err := json.Marshal(reminder)
+	if err != nil {
+		return fmt.Errorf("failed to encode reminder: %w", err)
+	}
+
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// reminderMessage describes a reminder in one line
+func reminderMessage(reminder entity.Reminder, dateFormat string) string {
+	task := reminder.Task
+	due := task.Due.Local().Format(dateFormat)
+	if reminder.Kind == entity.ReminderOverdue {
+		return fmt.Sprintf("Task %d %q is overdue (due %s)", task.ID, task.Title, due)
+	}
+	return fmt.Sprintf("Task %d %q is due %s", task.ID, task.Title, due)
+}
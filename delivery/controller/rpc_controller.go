@@ -0,0 +1,323 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+	"github.com/Illuminateee/task-tracker.git/manager"
+)
+
+// JSON-RPC 2.0 error codes
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcInternalError  = -32603
+	rpcTaskNotFound   = -32001
+	rpcConflict       = -32002
+)
+
+// rpcWatchInterval is how often the data file is checked for changes
+const rpcWatchInterval = time.Second
+
+// RPCController serves task operations as JSON-RPC 2.0 over a byte stream
+type RPCController struct {
+	taskManager  *manager.TaskManager
+	dataFilePath string
+
+	// mu serializes access to the task manager and the file snapshot
+	mu       sync.Mutex
+	lastStat fileSnapshot
+
+	// writeMu serializes writes of responses and notifications
+	writeMu sync.Mutex
+}
+
+// fileSnapshot identifies a version of the data file on disk
+type fileSnapshot struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+// rpcRequest is an incoming JSON-RPC request or notification
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+}
+
+// rpcResponse is an outgoing JSON-RPC response
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// rpcNotification is an outgoing JSON-RPC notification
+type rpcNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+// rpcError is a JSON-RPC error object
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// rpcParams holds the union of parameters accepted by the task methods
+type rpcParams struct {
+	ID          *int    `json:"id"`
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	Status      string  `json:"status"`
+	Filter      string  `json:"filter"`
+	// Version, when set, rejects the change if the task has changed since
+	Version *int `json:"version"`
+}
+
+// expectedVersion returns the version the client expects, or entity.AnyVersion when it sent none
+func (p *rpcParams) expectedVersion() (int, *rpcError) {
+	if p.Version == nil {
+		return entity.AnyVersion, nil
+	}
+	if *p.Version < 0 {
+		return 0, &rpcError{Code: rpcInvalidParams, Message: "invalid param: version"}
+	}
+	return *p.Version, nil
+}
+
+// NewRPCController creates a new JSON-RPC controller
+func NewRPCController(taskManager *manager.TaskManager, dataFilePath string) *RPCController {
+	return &RPCController{
+		taskManager:  taskManager,
+		dataFilePath: dataFilePath,
+	}
+}
+
+// Serve reads requests from r and writes responses to w until r is exhausted or ctx is done
+func (rc *RPCController) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rc.mu.Lock()
+	rc.lastStat = rc.statDataFile()
+	rc.mu.Unlock()
+
+	go rc.watch(ctx, w)
+
+	decoder := json.NewDecoder(r)
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				rc.write(w, rpcResponse{JSONRPC: "2.0", Error: &rpcError{Code: rpcParseError, Message: err.Error()}, ID: json.RawMessage("null")})
+				return nil
+			}
+			return fmt.Errorf("failed to read request: %w", err)
+		}
+
+		if response := rc.handleMessage(raw); response != nil {
+			rc.write(w, response)
+		}
+	}
+}
+
+// handleMessage dispatches a single request or a batch, returning nil when no reply is due
+func (rc *RPCController) handleMessage(raw json.RawMessage) any {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		if response := rc.handleRequest(raw); response != nil {
+			return response
+		}
+		return nil
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(raw, &batch); err != nil || len(batch) == 0 {
+		return rpcResponse{JSONRPC: "2.0", Error: &rpcError{Code: rpcInvalidRequest, Message: "invalid batch"}, ID: json.RawMessage("null")}
+	}
+
+	var responses []*rpcResponse
+	for _, item := range batch {
+		if response := rc.handleRequest(item); response != nil {
+			responses = append(responses, response)
+		}
+	}
+	if len(responses) == 0 {
+		return nil
+	}
+	return responses
+}
+
+// handleRequest executes a single request, returning nil for notifications
+func (rc *RPCController) handleRequest(raw json.RawMessage) *rpcResponse {
+	var req rpcRequest
+	if err := json.Unmarshal(raw, &req); err != nil || req.JSONRPC != "2.0" || req.Method == "" {
+		return &rpcResponse{JSONRPC: "2.0", Error: &rpcError{Code: rpcInvalidRequest, Message: "invalid request"}, ID: json.RawMessage("null")}
+	}
+
+	result, rpcErr := rc.call(req.Method, req.Params)
+	if req.ID == nil {
+		return nil
+	}
+
+	response := &rpcResponse{JSONRPC: "2.0", ID: req.ID}
+	if rpcErr != nil {
+		response.Error = rpcErr
+	} else {
+		response.Result = result
+	}
+	return response
+}
+
+// call invokes the task manager method named by method
+func (rc *RPCController) call(method string, rawParams json.RawMessage) (any, *rpcError) {
+	var params rpcParams
+	if len(rawParams) > 0 {
+		if err := json.Unmarshal(rawParams, &params); err != nil {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: fmt.Sprintf("invalid params: %v", err)}
+		}
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	// Our own writes should not be reported back as external changes
+	defer func() { rc.lastStat = rc.statDataFile() }()
+
+	var result any
+	var err error
+	switch method {
+	case "tasks.list":
+		var tasks []*entity.Task
+		tasks, err = rc.taskManager.ListTasks(strings.ToLower(params.Filter))
+		if tasks == nil {
+			tasks = []*entity.Task{}
+		}
+		result = tasks
+	case "tasks.get":
+		if params.ID == nil {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: "missing required param: id"}
+		}
+		result, err = rc.taskManager.GetTask(*params.ID)
+	case "tasks.add":
+		title, description := "", ""
+		if params.Title != nil {
+			title = *params.Title
+		}
+		if params.Description != nil {
+			description = *params.Description
+		}
+		result, err = rc.taskManager.AddTask(title, description)
+	case "tasks.update":
+		if params.ID == nil {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: "missing required param: id"}
+		}
+		title, description := "", ""
+		if params.Title != nil {
+			title = *params.Title
+		}
+		if params.Description != nil {
+			description = *params.Description
+		}
+		version, rpcErr := params.expectedVersion()
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		result, err = rc.taskManager.UpdateTaskAtVersion(*params.ID, version, title, description)
+	case "tasks.setStatus":
+		if params.ID == nil {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: "missing required param: id"}
+		}
+		version, rpcErr := params.expectedVersion()
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		result, err = rc.taskManager.UpdateTaskStatusAtVersion(*params.ID, version, params.Status)
+	case "tasks.delete":
+		if params.ID == nil {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: "missing required param: id"}
+		}
+		err = rc.taskManager.DeleteTask(*params.ID)
+		result = true
+	default:
+		return nil, &rpcError{Code: rpcMethodNotFound, Message: fmt.Sprintf("method not found: %s", method)}
+	}
+
+	if err != nil {
+		return nil, rc.toRPCError(err)
+	}
+	return result, nil
+}
+
+// toRPCError maps a domain error to a JSON-RPC error object
+func (rc *RPCController) toRPCError(err error) *rpcError {
+	switch ErrorCode(err) {
+	case ErrorCodeNotFound:
+		return &rpcError{Code: rpcTaskNotFound, Message: err.Error()}
+	case ErrorCodeConflict:
+		return &rpcError{Code: rpcConflict, Message: err.Error()}
+	case ErrorCodeUsage, ErrorCodeInvalidInput:
+		return &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+	default:
+		return &rpcError{Code: rpcInternalError, Message: err.Error()}
+	}
+}
+
+// watch polls the data file and sends a tasks.changed notification when it changes
+func (rc *RPCController) watch(ctx context.Context, w io.Writer) {
+	ticker := time.NewTicker(rpcWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		rc.mu.Lock()
+		current := rc.statDataFile()
+		changed := current != rc.lastStat
+		rc.lastStat = current
+		rc.mu.Unlock()
+
+		if changed {
+			rc.write(w, rpcNotification{JSONRPC: "2.0", Method: "tasks.changed"})
+		}
+	}
+}
+
+// statDataFile captures the current size and modification time of the data file
+func (rc *RPCController) statDataFile() fileSnapshot {
+	info, err := os.Stat(rc.dataFilePath)
+	if err != nil {
+		return fileSnapshot{}
+	}
+	return fileSnapshot{exists: true, size: info.Size(), modTime: info.ModTime()}
+}
+
+// write encodes v as a single line of JSON
+func (rc *RPCController) write(w io.Writer, v any) {
+	rc.writeMu.Lock()
+	defer rc.writeMu.Unlock()
+	json.NewEncoder(w).Encode(v)
+}
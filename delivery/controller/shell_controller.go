@@ -0,0 +1,261 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Illuminateee/task-tracker.git/delivery/terminal"
+)
+
+const (
+	// shellPrompt is shown before each command
+	shellPrompt = "task-tracker> "
+	// shellHistoryLimit caps the number of lines kept in the history file
+	shellHistoryLimit = 1000
+)
+
+// ShellController runs an interactive prompt that dispatches to a CLIController
+type ShellController struct {
+	cli         *CLIController
+	editor      *terminal.LineEditor
+	historyPath string
+}
+
+// NewShellController creates a new shell bound to the given CLI controller
+func NewShellController(cli *CLIController) *ShellController {
+	shell := &ShellController{
+		cli:         cli,
+		editor:      terminal.NewLineEditor(os.Stdin, os.Stdout),
+		historyPath: shellHistoryPath(),
+	}
+	shell.editor.Complete = shell.complete
+	return shell
+}
+
+// Run reads and executes commands until exit, quit or end of input
+func (s *ShellController) Run() error {
+	s.loadHistory()
+
+	// Warnings are held while a line is edited in raw mode and shown before the next prompt
+	held, release := holdWarnings(s.cli.warnings)
+	defer release()
+
+	fmt.Println("Task Tracker shell. Type 'help' for commands, 'exit' to quit.")
+	for {
+		for _, warning := range held.take() {
+			fmt.Fprintln(os.Stderr, warning)
+		}
+		line, err := s.editor.ReadLine(shellPrompt)
+		if errors.Is(err, terminal.ErrInterrupted) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read command: %w", err)
+		}
+
+		args, err := splitArgs(line)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			continue
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		s.editor.AddHistory(line)
+		s.saveHistory()
+
+		switch strings.ToLower(args[0]) {
+		case "exit", "quit":
+			return nil
+		case "shell":
+			fmt.Fprintln(os.Stderr, "Error: already in the shell")
+			continue
+		}
+
+		if err := s.cli.HandleCommand(args); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+}
+
+// complete offers completions for the word under the cursor
+func (s *ShellController) complete(line []rune, pos int) (int, []terminal.Completion) {
+	args, start, word := completionContext(line[:pos])
+	if len(args) == 0 {
+		return start, matchWords([]string{"exit", "quit"}, word, s.cli.completeArgs(args, word))
+	}
+	return start, s.cli.completeArgs(args, word)
+}
+
+// loadHistory reads previously entered commands from disk
+func (s *ShellController) loadHistory() {
+	if !s.keepsHistory() {
+		return
+	}
+	data, err := os.ReadFile(s.historyPath)
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		s.editor.AddHistory(line)
+	}
+}
+
+// saveHistory writes the most recent commands to disk, ignoring failures
+func (s *ShellController) saveHistory() {
+	if !s.keepsHistory() {
+		return
+	}
+
+	history := s.editor.History
+	if len(history) > shellHistoryLimit {
+		history = history[len(history)-shellHistoryLimit:]
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.historyPath), 0755); err != nil {
+		return
+	}
+	os.WriteFile(s.historyPath, []byte(strings.Join(history, "\n")+"\n"), 0600)
+}
+
+// keepsHistory reports whether history is read from and saved to disk. The
+// history holds task titles in plaintext, so it stays in memory while the
+// store is encrypted, which is checked again on exit in case the session
+// encrypted it
+func (s *ShellController) keepsHistory() bool {
+	if s.historyPath == "" {
+		return false
+	}
+	encryption, err := s.cli.taskManager.StorageEncryption()
+	return err == nil && !encryption.Encrypted
+}
+
+// shellHistoryPath returns the history file location, or "" when unavailable
+func shellHistoryPath() string {
+	if path := os.Getenv("TASK_TRACKER_HISTORY"); path != "" {
+		return path
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(cacheDir, "task-tracker", "shell_history")
+}
+
+// shellWord is an argument split from a command line; quoted is set when any
+// part of it was quoted or escaped, so it cannot be an operator such as &&
+type shellWord struct {
+	text   string
+	quoted bool
+}
+
+// splitArgs splits a command line into arguments, honouring quotes and backslash escapes
+func splitArgs(line string) ([]string, error) {
+	words, err := splitWords(line)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, len(words))
+	for i, word := range words {
+		args[i] = word.text
+	}
+	return args, nil
+}
+
+// splitWords splits a command line like splitArgs, remembering which words were quoted
+func splitWords(line string) ([]shellWord, error) {
+	var args []shellWord
+	var current strings.Builder
+	inArg := false
+	quoted := false
+	var quote rune
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != '\'' && r == '\\' && i+1 < len(runes):
+			i++
+			current.WriteRune(runes[i])
+			inArg, quoted = true, true
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			inArg, quoted = true, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, shellWord{text: current.String(), quoted: quoted})
+				current.Reset()
+				inArg, quoted = false, false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, usageErrorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, shellWord{text: current.String(), quoted: quoted})
+	}
+	return args, nil
+}
+
+// completionContext returns the complete arguments before the cursor plus the
+// start offset and raw text of the word being typed
+func completionContext(line []rune) (args []string, start int, word string) {
+	start = len(line)
+	inWord := false
+	var quote rune
+
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == ' ' || r == '\t':
+			if inWord {
+				parsed, _ := splitArgs(string(line[start:i]))
+				args = append(args, parsed...)
+				inWord = false
+			}
+			start = i + 1
+		default:
+			if !inWord {
+				start = i
+				inWord = true
+			}
+			if r == '"' || r == '\'' {
+				quote = r
+			}
+		}
+	}
+
+	if !inWord {
+		start = len(line)
+	}
+	return args, start, string(line[start:])
+}
+
+// quoteArg quotes s for splitArgs when it contains spaces or quotes
+func quoteArg(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\"'\\") {
+		return s
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + replacer.Replace(s) + `"`
+}
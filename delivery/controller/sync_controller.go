@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+	"github.com/Illuminateee/task-tracker.git/usecase"
+)
+
+// syncPath is the endpoint devices post their changes to
+const syncPath = "/sync"
+
+// maxSyncBody bounds the size of a sync request, which carries every change
+// a device made since its last sync
+const maxSyncBody = 32 << 20
+
+// SyncController serves the sync endpoint for devices running sync
+type SyncController struct {
+	server *usecase.SyncServer
+	// token, when set, must be sent by devices as a bearer token
+	token string
+}
+
+// NewSyncController creates a new sync controller
+func NewSyncController(server *usecase.SyncServer, token string) *SyncController {
+	return &SyncController{
+		server: server,
+		token:  token,
+	}
+}
+
+// Routes returns the HTTP handler serving the sync endpoint
+func (s *SyncController) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+syncPath, s.handleSync)
+	return mux
+}
+
+// handleSync serves POST /sync
+func (s *SyncController) handleSync(w http.ResponseWriter, r *http.Request) {
+	if s.token != "" {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			s.writeJSON(w, http.StatusUnauthorized, errorResponse{Error: "missing or invalid sync token"})
+			return
+		}
+	}
+
+	var req usecase.SyncRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSyncBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		s.writeError(w, fmt.Errorf("%w: invalid request body: %w", entity.ErrInvalidInput, err))
+		return
+	}
+
+	response, err := s.server.Exchange(req)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, response)
+}
+
+// writeJSON writes v as a JSON response with the given status code
+func (s *SyncController) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError maps an error to an HTTP status code and writes it as JSON
+func (s *SyncController) writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		status = http.StatusRequestEntityTooLarge
+	case ErrorCode(err) == ErrorCodeInvalidInput:
+		status = http.StatusBadRequest
+	}
+	s.writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// newHTTPSyncExchange returns a SyncExchange posting to the sync server at baseURL
+func newHTTPSyncExchange(baseURL, token string) usecase.SyncExchange {
+	client := &http.Client{Timeout: 30 * time.Second}
+	endpoint := strings.TrimSuffix(baseURL, "/") + syncPath
+
+	return func(req usecase.SyncRequest) (*usecase.SyncResponse, error) {
+		body, err := json.Marshal(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode sync request: %w", err)
+		}
+		httpReq, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid sync URL %q: %v", entity.ErrInvalidInput, baseURL, err)
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := client.Do(httpReq)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reach sync server: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			var failure errorResponse
+			data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+			if json.Unmarshal(data, &failure) != nil || failure.Error == "" {
+				failure.Error = strings.TrimSpace(string(data))
+			}
+			return nil, fmt.Errorf("sync server returned %s: %s", resp.Status, failure.Error)
+		}
+
+		var response usecase.SyncResponse
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			return nil, fmt.Errorf("failed to decode sync response: %w", err)
+		}
+		return &response, nil
+	}
+}
@@ -0,0 +1,436 @@
+package controller
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/Illuminateee/task-tracker.git/delivery/terminal"
+	"github.com/Illuminateee/task-tracker.git/entity"
+	"github.com/Illuminateee/task-tracker.git/manager"
+)
+
+// ANSI escape sequences used by the TUI
+const (
+	ansiEnterAltScreen = "\x1b[?1049h"
+	ansiExitAltScreen  = "\x1b[?1049l"
+	ansiHideCursor     = "\x1b[?25l"
+	ansiShowCursor     = "\x1b[?25h"
+	ansiClearScreen    = "\x1b[H\x1b[2J"
+	ansiClearLine      = "\x1b[2K"
+	ansiReverse        = "\x1b[7m"
+	ansiBold           = "\x1b[1m"
+	ansiDim            = "\x1b[2m"
+	ansiReset          = "\x1b[0m"
+)
+
+// tuiHelp is the key reference shown in the footer
+const tuiHelp = "↑/↓ move  space status  a add  e edit  d delete  / filter  q quit"
+
+// TUIController runs a full-screen, keyboard-driven task board
+type TUIController struct {
+	taskManager *manager.TaskManager
+	warnings    *log.Logger
+	in          *bufio.Reader
+	out         *bufio.Writer
+	fd          int
+
+	tasks   []*entity.Task
+	cursor  int
+	offset  int
+	query   string
+	message string
+	width   int
+	height  int
+}
+
+// NewTUIController creates a new TUI controller reading from stdin and drawing
+// to stdout; what is logged to warnings meanwhile shows in the message line
+func NewTUIController(taskManager *manager.TaskManager, warnings *log.Logger) *TUIController {
+	return &TUIController{
+		taskManager: taskManager,
+		warnings:    warnings,
+		in:          bufio.NewReader(os.Stdin),
+		out:         bufio.NewWriter(os.Stdout),
+		fd:          int(os.Stdin.Fd()),
+	}
+}
+
+// Run starts the interface and blocks until the user quits
+func (t *TUIController) Run() error {
+	if !terminal.IsTerminal(t.fd) {
+		return fmt.Errorf("tui command requires an interactive terminal")
+	}
+
+	// Released last, once the terminal is restored
+	held, release := holdWarnings(t.warnings)
+	defer release()
+
+	state, err := terminal.MakeRaw(t.fd)
+	if err != nil {
+		return fmt.Errorf("failed to enable raw terminal mode: %w", err)
+	}
+	defer terminal.Restore(t.fd, state)
+
+	t.out.WriteString(ansiEnterAltScreen + ansiHideCursor)
+	defer func() {
+		t.out.WriteString(ansiShowCursor + ansiExitAltScreen)
+		t.out.Flush()
+	}()
+
+	if err := t.reload(); err != nil {
+		return err
+	}
+
+	for {
+		if t.message == "" {
+			if warnings := held.take(); len(warnings) > 0 {
+				t.message = warnings[len(warnings)-1]
+			}
+		}
+		t.render("")
+		key, err := terminal.ReadKey(t.in)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to read key: %w", err)
+		}
+
+		t.message = ""
+		if quit := t.handleKey(key); quit {
+			return nil
+		}
+	}
+}
+
+// handleKey applies a key press and reports whether the TUI should exit
+func (t *TUIController) handleKey(key terminal.Key) bool {
+	switch key.Type {
+	case terminal.KeyCtrlC, terminal.KeyCtrlD:
+		return true
+	case terminal.KeyUp:
+		t.moveCursor(-1)
+	case terminal.KeyDown:
+		t.moveCursor(1)
+	case terminal.KeyHome:
+		t.moveCursor(-len(t.tasks))
+	case terminal.KeyEnd:
+		t.moveCursor(len(t.tasks))
+	case terminal.KeyEscape:
+		if t.query != "" {
+			t.query = ""
+			t.refresh()
+		}
+	case terminal.KeyRune:
+		switch key.Rune {
+		case 'q':
+			return true
+		case 'k':
+			t.moveCursor(-1)
+		case 'j':
+			t.moveCursor(1)
+		case ' ':
+			t.cycleStatus()
+		case 'a':
+			t.addTask()
+		case 'e':
+			t.editTask()
+		case 'd':
+			t.deleteTask()
+		case '/':
+			t.filterTasks()
+		case 'r':
+			t.refresh()
+		}
+	}
+	return false
+}
+
+// reload fetches all tasks and applies the current filter query
+func (t *TUIController) reload() error {
+	tasks, err := t.taskManager.ListAllTasks()
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	query := strings.ToLower(t.query)
+	t.tasks = t.tasks[:0]
+	for _, task := range tasks {
+		if query == "" ||
+			strings.Contains(strings.ToLower(task.Title), query) ||
+			strings.Contains(strings.ToLower(task.Description), query) ||
+			string(task.Status) == query {
+			t.tasks = append(t.tasks, task)
+		}
+	}
+
+	t.moveCursor(0)
+	return nil
+}
+
+// refresh reloads tasks, reporting failures in the message line
+func (t *TUIController) refresh() {
+	if err := t.reload(); err != nil {
+		t.message = err.Error()
+	}
+}
+
+// selectTask re-positions the cursor on the task with the given ID
+func (t *TUIController) selectTask(id int) {
+	for i, task := range t.tasks {
+		if task.ID == id {
+			t.cursor = i
+			t.moveCursor(0)
+			return
+		}
+	}
+}
+
+// moveCursor moves the selection by delta, keeping it visible
+func (t *TUIController) moveCursor(delta int) {
+	t.cursor += delta
+	if t.cursor >= len(t.tasks) {
+		t.cursor = len(t.tasks) - 1
+	}
+	if t.cursor < 0 {
+		t.cursor = 0
+	}
+
+	rows := t.listHeight()
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	}
+	if t.cursor >= t.offset+rows {
+		t.offset = t.cursor - rows + 1
+	}
+}
+
+// selected returns the task under the cursor, or nil when the list is empty
+func (t *TUIController) selected() *entity.Task {
+	if len(t.tasks) == 0 {
+		return nil
+	}
+	return t.tasks[t.cursor]
+}
+
+// cycleStatus advances the selected task through todo, in-progress and done
+func (t *TUIController) cycleStatus() {
+	task := t.selected()
+	if task == nil {
+		return
+	}
+
+	next := entity.TaskStatusToDo
+	switch task.Status {
+	case entity.TaskStatusToDo:
+		next = entity.TaskStatusInProgress
+	case entity.TaskStatusInProgress:
+		next = entity.TaskStatusDone
+	}
+
+	if _, err := t.taskManager.UpdateTaskStatusAtVersion(task.ID, task.Version, string(next)); err != nil {
+		t.message = err.Error()
+		t.refresh()
+		return
+	}
+	t.message = fmt.Sprintf("Task %d marked as %s", task.ID, next)
+	t.refresh()
+	t.selectTask(task.ID)
+}
+
+// addTask prompts for a title and description and creates a task
+func (t *TUIController) addTask() {
+	title, ok := t.prompt("Title: ", "")
+	if !ok || strings.TrimSpace(title) == "" {
+		return
+	}
+	description, ok := t.prompt("Description: ", "")
+	if !ok {
+		return
+	}
+
+	task, err := t.taskManager.AddTask(title, description)
+	if err != nil {
+		t.message = err.Error()
+		return
+	}
+	t.message = fmt.Sprintf("Task added successfully (ID: %d)", task.ID)
+	t.refresh()
+	t.selectTask(task.ID)
+}
+
+// editTask prompts for a new title and description for the selected task
+func (t *TUIController) editTask() {
+	task := t.selected()
+	if task == nil {
+		return
+	}
+
+	title, ok := t.prompt("Title: ", task.Title)
+	if !ok {
+		return
+	}
+	description, ok := t.prompt("Description: ", task.Description)
+	if !ok {
+		return
+	}
+
+	// The prompt may take a while, so refuse to overwrite concurrent edits
+	if _, err := t.taskManager.UpdateTaskAtVersion(task.ID, task.Version, title, description); err != nil {
+		t.message = err.Error()
+		t.refresh()
+		return
+	}
+	t.message = fmt.Sprintf("Task %d updated successfully", task.ID)
+	t.refresh()
+	t.selectTask(task.ID)
+}
+
+// deleteTask deletes the selected task after confirmation
+func (t *TUIController) deleteTask() {
+	task := t.selected()
+	if task == nil {
+		return
+	}
+
+	answer, ok := t.prompt(fmt.Sprintf("Delete task %d? (y/N) ", task.ID), "")
+	if !ok || !strings.EqualFold(strings.TrimSpace(answer), "y") {
+		return
+	}
+
+	if err := t.taskManager.DeleteTask(task.ID); err != nil {
+		t.message = err.Error()
+		return
+	}
+	t.message = fmt.Sprintf("Task %d deleted successfully", task.ID)
+	t.refresh()
+}
+
+// filterTasks prompts for a filter query matching title, description or status
+func (t *TUIController) filterTasks() {
+	query, ok := t.prompt("Filter: ", t.query)
+	if !ok {
+		return
+	}
+	t.query = strings.TrimSpace(query)
+	t.cursor = 0
+	t.refresh()
+}
+
+// prompt reads a line of input on the bottom row; ok is false when cancelled
+func (t *TUIController) prompt(label, initial string) (string, bool) {
+	input := []rune(initial)
+	for {
+		t.render(label + string(input) + "█")
+		key, err := terminal.ReadKey(t.in)
+		if err != nil {
+			return "", false
+		}
+
+		switch key.Type {
+		case terminal.KeyEnter:
+			return string(input), true
+		case terminal.KeyEscape, terminal.KeyCtrlC:
+			return "", false
+		case terminal.KeyBackspace:
+			if len(input) > 0 {
+				input = input[:len(input)-1]
+			}
+		case terminal.KeyCtrlU:
+			input = input[:0]
+		case terminal.KeyRune:
+			input = append(input, key.Rune)
+		}
+	}
+}
+
+// listHeight returns the number of rows available for tasks
+func (t *TUIController) listHeight() int {
+	// Header, blank line, blank line, footer and message line
+	if rows := t.height - 5; rows > 0 {
+		return rows
+	}
+	return 1
+}
+
+// render redraws the whole screen; a non-empty input replaces the message line
+func (t *TUIController) render(input string) {
+	if width, height, err := terminal.Size(t.fd); err == nil {
+		t.width, t.height = width, height
+	}
+	if t.width <= 0 {
+		t.width = 80
+	}
+	if t.height <= 0 {
+		t.height = 24
+	}
+
+	var b strings.Builder
+	b.WriteString(ansiClearScreen)
+
+	header := fmt.Sprintf("Task Tracker — %d task(s)", len(t.tasks))
+	if t.query != "" {
+		header += fmt.Sprintf(" matching '%s'", t.query)
+	}
+	b.WriteString(ansiBold + truncate(header, t.width) + ansiReset + "\r\n\r\n")
+
+	rows := t.listHeight()
+	if len(t.tasks) == 0 {
+		b.WriteString(ansiDim + "No tasks found. Press 'a' to add one." + ansiReset + "\r\n")
+		rows--
+	}
+	for i := t.offset; i < len(t.tasks) && i < t.offset+rows; i++ {
+		line := truncate(t.formatRow(t.tasks[i]), t.width)
+		if i == t.cursor {
+			line = ansiReverse + line + strings.Repeat(" ", max(0, t.width-len([]rune(line)))) + ansiReset
+		}
+		b.WriteString(line + "\r\n")
+	}
+
+	// Anchor the footer to the bottom of the screen
+	fmt.Fprintf(&b, "\x1b[%d;1H%s", t.height-1, ansiClearLine)
+	b.WriteString(ansiDim + truncate(tuiHelp, t.width) + ansiReset)
+	fmt.Fprintf(&b, "\x1b[%d;1H%s", t.height, ansiClearLine)
+	if input != "" {
+		b.WriteString(truncate(input, t.width))
+	} else {
+		b.WriteString(truncate(t.message, t.width))
+	}
+
+	t.out.WriteString(b.String())
+	t.out.Flush()
+}
+
+// formatRow formats a task as a single board row
+func (t *TUIController) formatRow(task *entity.Task) string {
+	mark := "[ ]"
+	switch task.Status {
+	case entity.TaskStatusInProgress:
+		mark = "[~]"
+	case entity.TaskStatusDone:
+		mark = "[x]"
+	}
+
+	row := fmt.Sprintf(" %s %4d  %s", mark, task.ID, task.Title)
+	if task.Description != "" {
+		row += "  — " + task.Description
+	}
+	return row
+}
+
+// truncate shortens s to at most width runes, marking the cut with an ellipsis
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string(runes[:max(0, width)])
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+	"github.com/Illuminateee/task-tracker.git/usecase"
+)
+
+// Headers sent with every webhook delivery
+const (
+	webhookEventHeader     = "X-Task-Tracker-Event"
+	webhookDeliveryHeader  = "X-Task-Tracker-Delivery"
+	webhookSignatureHeader = "X-Task-Tracker-Signature-256"
+)
+
+// webhookTimeout bounds a single webhook delivery
+const webhookTimeout = 10 * time.Second
+
+// newWebhookPost returns a WebhookPost that signs each payload with secret
+// and posts it, treating any non-2xx response as a failure
+func newWebhookPost(secret string) usecase.WebhookPost {
+	client := &http.Client{Timeout: webhookTimeout}
+
+	return func(entry *entity.OutboxEntry) error {
+		// The outbox file stores the payload indented; send it compact
+		var body bytes.Buffer
+		if err := json.Compact(&body, entry.Payload); err != nil {
+			return fmt.Errorf("invalid webhook payload: %w", err)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, entry.URL, bytes.NewReader(body.Bytes()))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "task-tracker")
+		req.Header.Set(webhookEventHeader, string(entry.Event))
+		req.Header.Set(webhookDeliveryHeader, entry.ID)
+		req.Header.Set(webhookSignatureHeader, signWebhook(secret, body.Bytes()))
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+		}
+		return nil
+	}
+}
+
+// signWebhook returns the signature header value for a payload: the hex
+// HMAC-SHA256 of the body keyed with secret, prefixed with "sha256="
+func signWebhook(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,221 @@
+package controller
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+	"github.com/Illuminateee/task-tracker.git/manager"
+	"github.com/Illuminateee/task-tracker.git/repository"
+	"github.com/Illuminateee/task-tracker.git/usecase"
+)
+
+// webhookReceiver records the deliveries made to it and answers with status
+// after delay
+type webhookReceiver struct {
+	mu       sync.Mutex
+	status   int
+	delay    time.Duration
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (wr *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	time.Sleep(wr.delay)
+
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	wr.requests = append(wr.requests, r)
+	wr.bodies = append(wr.bodies, body)
+	w.WriteHeader(wr.status)
+}
+
+func (wr *webhookReceiver) setStatus(status int) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	wr.status = status
+}
+
+func (wr *webhookReceiver) count() int {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	return len(wr.requests)
+}
+
+// newWebhookManager returns a task manager for the tasks file at path that
+// posts every change to url, failing the test on background delivery errors
+// unless allowErrors is set
+func newWebhookManager(t *testing.T, path, url string, allowErrors bool) *manager.TaskManager {
+	t.Helper()
+	tm := manager.NewTaskManager(path, repository.FileOptions{})
+	tm.EnableWebhooks([]string{url}, newWebhookPost("s3cret"), func(err error) {
+		if !allowErrors {
+			t.Errorf("unexpected webhook error: %v", err)
+		}
+	})
+	return tm
+}
+
+func TestWebhookDeliverySignsPayload(t *testing.T) {
+	receiver := &webhookReceiver{status: http.StatusNoContent}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	tm := newWebhookManager(t, filepath.Join(t.TempDir(), "tasks.json"), server.URL, false)
+	if _, err := tm.AddTask("Write tests", ""); err != nil {
+		t.Fatal(err)
+	}
+	if !tm.WaitForWebhooks(5 * time.Second) {
+		t.Fatal("delivery did not finish")
+	}
+
+	if receiver.count() != 1 {
+		t.Fatalf("receiver got %d requests, want 1", receiver.count())
+	}
+	req, body := receiver.requests[0], receiver.bodies[0]
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if got := req.Header.Get(webhookSignatureHeader); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+	if got := req.Header.Get(webhookEventHeader); got != string(entity.TaskAdded) {
+		t.Errorf("event header = %q, want %q", got, entity.TaskAdded)
+	}
+
+	var payload usecase.WebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.ID == "" || payload.ID != req.Header.Get(webhookDeliveryHeader) {
+		t.Errorf("payload ID %q does not match delivery header %q", payload.ID, req.Header.Get(webhookDeliveryHeader))
+	}
+	if payload.After == nil || payload.After.Title != "Write tests" {
+		t.Errorf("payload after = %+v, want the created task", payload.After)
+	}
+
+	pending, err := tm.PendingWebhooks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("%d deliveries still pending after success", len(pending))
+	}
+}
+
+func TestWebhookDeliveryRetriesAfterFailureAcrossRestart(t *testing.T) {
+	receiver := &webhookReceiver{status: http.StatusInternalServerError}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	tm := newWebhookManager(t, path, server.URL, true)
+	start := time.Now()
+	if _, err := tm.AddTask("Flaky receiver", ""); err != nil {
+		t.Fatal(err)
+	}
+	if !tm.WaitForWebhooks(5 * time.Second) {
+		t.Fatal("delivery did not finish")
+	}
+
+	pending, err := tm.PendingWebhooks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 {
+		t.Fatalf("%d deliveries pending after a 500, want 1", len(pending))
+	}
+	entry := pending[0]
+	if entry.Attempts != 1 || entry.Failed {
+		t.Errorf("attempts = %d, failed = %v; want 1 attempt, not failed", entry.Attempts, entry.Failed)
+	}
+	if entry.NextAttempt.Before(start.Add(10 * time.Second)) {
+		t.Errorf("next attempt %s is sooner than the 10s backoff after %s", entry.NextAttempt, start)
+	}
+
+	// The entry backs off, so a flush of due deliveries leaves it alone
+	result, err := tm.FlushWebhooks(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Delivered+result.Retrying+result.Failed != 0 || receiver.count() != 1 {
+		t.Errorf("flush during backoff made %d requests in total, want 1", receiver.count())
+	}
+
+	info, err := os.Stat(path + ".outbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("outbox permissions = %o, want 600", perm)
+	}
+
+	// A new process picks up the queued delivery once the receiver recovers
+	receiver.setStatus(http.StatusOK)
+	restarted := newWebhookManager(t, path, server.URL, false)
+	result, err = restarted.FlushWebhooks(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Delivered != 1 {
+		t.Errorf("delivered %d after restart, want 1", result.Delivered)
+	}
+	if receiver.count() != 2 {
+		t.Errorf("receiver got %d requests, want 2", receiver.count())
+	}
+	if string(receiver.bodies[0]) != string(receiver.bodies[1]) {
+		t.Error("retried delivery differs from the first attempt")
+	}
+	if _, err := os.Stat(path + ".outbox"); !os.IsNotExist(err) {
+		t.Errorf("outbox file left behind after every delivery succeeded: %v", err)
+	}
+}
+
+func TestWebhookFlushesSharingAnOutboxDeliverOnce(t *testing.T) {
+	// Slow answers keep both flushes running at the same time
+	receiver := &webhookReceiver{status: http.StatusServiceUnavailable, delay: 20 * time.Millisecond}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	// Queue deliveries while the receiver is down, then let two processes
+	// flush the same outbox at once
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	tm := newWebhookManager(t, path, server.URL, true)
+	for range 12 {
+		if _, err := tm.AddTask("Queued", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !tm.WaitForWebhooks(10 * time.Second) {
+		t.Fatal("delivery did not finish")
+	}
+	failed := receiver.count()
+	receiver.setStatus(http.StatusOK)
+
+	var wg sync.WaitGroup
+	for range 2 {
+		tm := newWebhookManager(t, path, server.URL, false)
+		wg.Go(func() {
+			if _, err := tm.FlushWebhooks(true); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if delivered := receiver.count() - failed; delivered != 12 {
+		t.Errorf("receiver got %d requests after recovering, want each of the 12 deliveries once", delivered)
+	}
+}
@@ -0,0 +1,147 @@
+package terminal
+
+import (
+	"bufio"
+	"unicode/utf8"
+)
+
+// KeyType identifies the kind of key read from the terminal
+type KeyType int
+
+const (
+	KeyRune KeyType = iota
+	KeyEnter
+	KeyBackspace
+	KeyDelete
+	KeyTab
+	KeyEscape
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyHome
+	KeyEnd
+	KeyCtrlA
+	KeyCtrlC
+	KeyCtrlD
+	KeyCtrlE
+	KeyCtrlK
+	KeyCtrlU
+	KeyCtrlW
+	KeyUnknown
+)
+
+// Key is a single decoded key press
+type Key struct {
+	Type KeyType
+	Rune rune
+}
+
+// ReadKey reads and decodes the next key press from a raw-mode terminal
+func ReadKey(r *bufio.Reader) (Key, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch b {
+	case '\r', '\n':
+		return Key{Type: KeyEnter}, nil
+	case 127, 8:
+		return Key{Type: KeyBackspace}, nil
+	case '\t':
+		return Key{Type: KeyTab}, nil
+	case 1:
+		return Key{Type: KeyCtrlA}, nil
+	case 3:
+		return Key{Type: KeyCtrlC}, nil
+	case 4:
+		return Key{Type: KeyCtrlD}, nil
+	case 5:
+		return Key{Type: KeyCtrlE}, nil
+	case 11:
+		return Key{Type: KeyCtrlK}, nil
+	case 21:
+		return Key{Type: KeyCtrlU}, nil
+	case 23:
+		return Key{Type: KeyCtrlW}, nil
+	case 27:
+		return readEscape(r)
+	}
+
+	if b < 32 {
+		return Key{Type: KeyUnknown}, nil
+	}
+
+	if b < utf8.RuneSelf {
+		return Key{Type: KeyRune, Rune: rune(b)}, nil
+	}
+
+	// Multi-byte UTF-8 sequence
+	buf := []byte{b}
+	for !utf8.FullRune(buf) && len(buf) < utf8.UTFMax {
+		next, err := r.ReadByte()
+		if err != nil {
+			return Key{}, err
+		}
+		buf = append(buf, next)
+	}
+	ch, _ := utf8.DecodeRune(buf)
+	return Key{Type: KeyRune, Rune: ch}, nil
+}
+
+// readEscape decodes an escape sequence, treating a lone ESC as the escape key
+func readEscape(r *bufio.Reader) (Key, error) {
+	// Escape sequences arrive in a single read; a lone ESC has nothing buffered after it
+	if r.Buffered() == 0 {
+		return Key{Type: KeyEscape}, nil
+	}
+
+	b, err := r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+	if b != '[' && b != 'O' {
+		return Key{Type: KeyUnknown}, nil
+	}
+
+	var params []byte
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			return Key{}, err
+		}
+		if c >= 0x40 && c <= 0x7e {
+			return decodeCSI(params, c), nil
+		}
+		params = append(params, c)
+	}
+}
+
+// decodeCSI maps a control sequence to a key
+func decodeCSI(params []byte, final byte) Key {
+	switch final {
+	case 'A':
+		return Key{Type: KeyUp}
+	case 'B':
+		return Key{Type: KeyDown}
+	case 'C':
+		return Key{Type: KeyRight}
+	case 'D':
+		return Key{Type: KeyLeft}
+	case 'H':
+		return Key{Type: KeyHome}
+	case 'F':
+		return Key{Type: KeyEnd}
+	case '~':
+		switch string(params) {
+		case "1", "7":
+			return Key{Type: KeyHome}
+		case "4", "8":
+			return Key{Type: KeyEnd}
+		case "3":
+			return Key{Type: KeyDelete}
+		}
+	}
+	return Key{Type: KeyUnknown}
+}
@@ -0,0 +1,239 @@
+package terminal
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrInterrupted is returned by ReadLine when the user presses Ctrl-C
+var ErrInterrupted = errors.New("interrupted")
+
+// Completion is a candidate offered by tab completion
+type Completion struct {
+	// Value replaces the word being completed
+	Value string
+	// Description is shown next to the value when listing candidates
+	Description string
+}
+
+// Completer returns the start of the word being completed and its candidates
+type Completer func(line []rune, pos int) (start int, candidates []Completion)
+
+// LineEditor reads lines with cursor movement, history and tab completion
+type LineEditor struct {
+	in  *bufio.Reader
+	out *bufio.Writer
+	fd  int
+
+	// History holds previous lines, oldest first
+	History []string
+	// Complete is consulted when the user presses Tab
+	Complete Completer
+}
+
+// NewLineEditor creates a line editor reading from in and echoing to out
+func NewLineEditor(in *os.File, out io.Writer) *LineEditor {
+	return &LineEditor{
+		in:  bufio.NewReader(in),
+		out: bufio.NewWriter(out),
+		fd:  int(in.Fd()),
+	}
+}
+
+// ReadLine shows prompt and returns the entered line, or io.EOF on Ctrl-D at an empty prompt
+func (e *LineEditor) ReadLine(prompt string) (string, error) {
+	if !IsTerminal(e.fd) {
+		return e.readPlainLine(prompt)
+	}
+
+	state, err := MakeRaw(e.fd)
+	if err != nil {
+		return e.readPlainLine(prompt)
+	}
+	defer Restore(e.fd, state)
+
+	line := []rune{}
+	pos := 0
+	// historyIndex == len(History) means editing a new line
+	historyIndex := len(e.History)
+	draft := ""
+
+	e.redraw(prompt, line, pos)
+	for {
+		key, err := ReadKey(e.in)
+		if err != nil {
+			return "", err
+		}
+
+		switch key.Type {
+		case KeyEnter:
+			e.out.WriteString("\r\n")
+			e.out.Flush()
+			return string(line), nil
+		case KeyCtrlC:
+			e.out.WriteString("^C\r\n")
+			e.out.Flush()
+			return "", ErrInterrupted
+		case KeyCtrlD:
+			if len(line) == 0 {
+				e.out.WriteString("\r\n")
+				e.out.Flush()
+				return "", io.EOF
+			}
+			if pos < len(line) {
+				line = append(line[:pos], line[pos+1:]...)
+			}
+		case KeyBackspace:
+			if pos > 0 {
+				line = append(line[:pos-1], line[pos:]...)
+				pos--
+			}
+		case KeyDelete:
+			if pos < len(line) {
+				line = append(line[:pos], line[pos+1:]...)
+			}
+		case KeyLeft:
+			if pos > 0 {
+				pos--
+			}
+		case KeyRight:
+			if pos < len(line) {
+				pos++
+			}
+		case KeyHome, KeyCtrlA:
+			pos = 0
+		case KeyEnd, KeyCtrlE:
+			pos = len(line)
+		case KeyCtrlK:
+			line = line[:pos]
+		case KeyCtrlU:
+			line = append([]rune{}, line[pos:]...)
+			pos = 0
+		case KeyCtrlW:
+			start := pos
+			for start > 0 && line[start-1] == ' ' {
+				start--
+			}
+			for start > 0 && line[start-1] != ' ' {
+				start--
+			}
+			line = append(line[:start], line[pos:]...)
+			pos = start
+		case KeyUp:
+			if historyIndex > 0 {
+				if historyIndex == len(e.History) {
+					draft = string(line)
+				}
+				historyIndex--
+				line = []rune(e.History[historyIndex])
+				pos = len(line)
+			}
+		case KeyDown:
+			if historyIndex < len(e.History) {
+				historyIndex++
+				if historyIndex == len(e.History) {
+					line = []rune(draft)
+				} else {
+					line = []rune(e.History[historyIndex])
+				}
+				pos = len(line)
+			}
+		case KeyTab:
+			line, pos = e.complete(prompt, line, pos)
+		case KeyRune:
+			line = append(line[:pos], append([]rune{key.Rune}, line[pos:]...)...)
+			pos++
+		}
+
+		e.redraw(prompt, line, pos)
+	}
+}
+
+// AddHistory appends a line to the history, skipping blanks and repeats
+func (e *LineEditor) AddHistory(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	if n := len(e.History); n > 0 && e.History[n-1] == line {
+		return
+	}
+	e.History = append(e.History, line)
+}
+
+// complete applies tab completion at pos, listing candidates when ambiguous
+func (e *LineEditor) complete(prompt string, line []rune, pos int) ([]rune, int) {
+	if e.Complete == nil {
+		return line, pos
+	}
+
+	start, candidates := e.Complete(line, pos)
+	if len(candidates) == 0 {
+		return line, pos
+	}
+
+	replacement := candidates[0].Value
+	if len(candidates) == 1 {
+		replacement += " "
+	} else {
+		replacement = commonPrefix(candidates)
+		if len([]rune(replacement)) <= pos-start {
+			// No progress possible, so show the options
+			e.out.WriteString("\r\n")
+			for _, candidate := range candidates {
+				if candidate.Description != "" {
+					fmt.Fprintf(e.out, "  %-20s %s\r\n", candidate.Value, candidate.Description)
+				} else {
+					fmt.Fprintf(e.out, "  %s\r\n", candidate.Value)
+				}
+			}
+			return line, pos
+		}
+	}
+
+	updated := append([]rune{}, line[:start]...)
+	updated = append(updated, []rune(replacement)...)
+	newPos := len(updated)
+	updated = append(updated, line[pos:]...)
+	return updated, newPos
+}
+
+// redraw repaints the prompt and line and places the cursor
+func (e *LineEditor) redraw(prompt string, line []rune, pos int) {
+	e.out.WriteString("\r" + prompt + string(line) + "\x1b[K")
+	if back := len(line) - pos; back > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dD", back)
+	}
+	e.out.Flush()
+}
+
+// readPlainLine reads a line without editing support when stdin is not a terminal
+func (e *LineEditor) readPlainLine(prompt string) (string, error) {
+	if IsTerminal(int(os.Stdout.Fd())) {
+		e.out.WriteString(prompt)
+		e.out.Flush()
+	}
+
+	line, err := e.in.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// commonPrefix returns the longest prefix shared by all candidate values
+func commonPrefix(candidates []Completion) string {
+	prefix := []rune(candidates[0].Value)
+	for _, candidate := range candidates[1:] {
+		value := []rune(candidate.Value)
+		n := 0
+		for n < len(prefix) && n < len(value) && prefix[n] == value[n] {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return string(prefix)
+}
@@ -0,0 +1,53 @@
+package terminal
+
+import (
+	"io"
+	"os"
+	"unicode/utf8"
+)
+
+// ReadPassword reads a line from the terminal in without echoing it; Ctrl-C
+// returns ErrInterrupted and Ctrl-D on an empty line returns io.EOF
+func ReadPassword(in *os.File) ([]byte, error) {
+	fd := int(in.Fd())
+	state, err := MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	defer Restore(fd, state)
+
+	var password []byte
+	buf := make([]byte, 1)
+	for {
+		n, err := in.Read(buf)
+		if n == 0 {
+			if err == nil || err == io.EOF {
+				if len(password) == 0 {
+					return nil, io.EOF
+				}
+				return password, nil
+			}
+			return nil, err
+		}
+
+		switch b := buf[0]; b {
+		case '\r', '\n':
+			return password, nil
+		case 3: // Ctrl-C
+			return nil, ErrInterrupted
+		case 4: // Ctrl-D
+			if len(password) == 0 {
+				return nil, io.EOF
+			}
+		case 8, 127: // Backspace
+			if len(password) > 0 {
+				_, size := utf8.DecodeLastRune(password)
+				password = password[:len(password)-size]
+			}
+		case 21: // Ctrl-U
+			password = password[:0]
+		default:
+			password = append(password, b)
+		}
+	}
+}
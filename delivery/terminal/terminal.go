@@ -0,0 +1,10 @@
+// Package terminal provides raw-mode terminal handling and key decoding
+// without depending on third-party libraries.
+package terminal
+
+import (
+	"errors"
+)
+
+// ErrUnsupported is returned on platforms without raw terminal support
+var ErrUnsupported = errors.New("terminal control is not supported on this platform")
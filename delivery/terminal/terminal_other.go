@@ -0,0 +1,26 @@
+//go:build !linux && !darwin
+
+package terminal
+
+// State holds the terminal settings to restore after raw mode
+type State struct{}
+
+// IsTerminal reports whether fd refers to a terminal
+func IsTerminal(fd int) bool {
+	return false
+}
+
+// MakeRaw puts the terminal into raw mode and returns the previous state
+func MakeRaw(fd int) (*State, error) {
+	return nil, ErrUnsupported
+}
+
+// Restore returns the terminal to a previously saved state
+func Restore(fd int, state *State) error {
+	return ErrUnsupported
+}
+
+// Size returns the width and height of the terminal
+func Size(fd int) (width, height int, err error) {
+	return 0, 0, ErrUnsupported
+}
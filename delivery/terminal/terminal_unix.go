@@ -0,0 +1,75 @@
+//go:build linux || darwin
+
+package terminal
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// State holds the terminal settings to restore after raw mode
+type State struct {
+	termios syscall.Termios
+}
+
+// IsTerminal reports whether fd refers to a terminal
+func IsTerminal(fd int) bool {
+	_, err := getTermios(fd)
+	return err == nil
+}
+
+// MakeRaw puts the terminal into raw mode and returns the previous state
+func MakeRaw(fd int) (*State, error) {
+	termios, err := getTermios(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	oldState := &State{termios: *termios}
+
+	termios.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	termios.Oflag &^= syscall.OPOST
+	termios.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	termios.Cflag &^= syscall.CSIZE | syscall.PARENB
+	termios.Cflag |= syscall.CS8
+	termios.Cc[syscall.VMIN] = 1
+	termios.Cc[syscall.VTIME] = 0
+
+	if err := setTermios(fd, termios); err != nil {
+		return nil, err
+	}
+	return oldState, nil
+}
+
+// Restore returns the terminal to a previously saved state
+func Restore(fd int, state *State) error {
+	return setTermios(fd, &state.termios)
+}
+
+// Size returns the width and height of the terminal
+func Size(fd int) (width, height int, err error) {
+	var ws struct {
+		Row, Col, Xpixel, Ypixel uint16
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws))); errno != 0 {
+		return 0, 0, errno
+	}
+	return int(ws.Col), int(ws.Row), nil
+}
+
+// getTermios reads the terminal settings for fd
+func getTermios(fd int) (*syscall.Termios, error) {
+	termios := &syscall.Termios{}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), ioctlGetTermios, uintptr(unsafe.Pointer(termios))); errno != 0 {
+		return nil, errno
+	}
+	return termios, nil
+}
+
+// setTermios applies terminal settings to fd
+func setTermios(fd int, termios *syscall.Termios) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), ioctlSetTermios, uintptr(unsafe.Pointer(termios))); errno != 0 {
+		return errno
+	}
+	return nil
+}
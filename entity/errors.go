@@ -0,0 +1,100 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrTaskNotFound is returned when a task with the requested ID does not exist
+	ErrTaskNotFound = errors.New("task not found")
+
+	// ErrInvalidInput is returned when user-supplied values fail validation
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrConflict is returned when a change clashes with the stored state
+	ErrConflict = errors.New("conflict")
+
+	// ErrStorage is returned when the task store cannot be read or written
+	ErrStorage = errors.New("storage error")
+)
+
+// TaskNotFoundError reports a missing task and matches ErrTaskNotFound
+type TaskNotFoundError struct {
+	ID int
+}
+
+// Error implements the error interface
+func (e *TaskNotFoundError) Error() string {
+	return fmt.Sprintf("task with ID %d not found", e.ID)
+}
+
+// Is allows errors.Is(err, ErrTaskNotFound) to match
+func (e *TaskNotFoundError) Is(target error) bool {
+	return target == ErrTaskNotFound
+}
+
+// ConflictError reports a change that clashes with a stored task and matches ErrConflict
+type ConflictError struct {
+	ID     int
+	Reason string
+}
+
+// NewVersionConflictError reports that a task changed after the caller loaded it
+func NewVersionConflictError(id, storedVersion, expectedVersion int) *ConflictError {
+	return &ConflictError{
+		ID:     id,
+		Reason: fmt.Sprintf("was modified concurrently (stored version %d, expected %d)", storedVersion, expectedVersion),
+	}
+}
+
+// Error implements the error interface
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("task with ID %d %s", e.ID, e.Reason)
+}
+
+// Is allows errors.Is(err, ErrConflict) to match
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// StorageError reports a failed storage operation and matches ErrStorage
+type StorageError struct {
+	// Op describes the failed operation, such as "read tasks file"
+	Op  string
+	Err error
+}
+
+// Error implements the error interface
+func (e *StorageError) Error() string {
+	return fmt.Sprintf("failed to %s: %v", e.Op, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *StorageError) Unwrap() error {
+	return e.Err
+}
+
+// Is allows errors.Is(err, ErrStorage) to match
+func (e *StorageError) Is(target error) bool {
+	return target == ErrStorage
+}
+
+// HookRejectedError reports a change vetoed by a hook and matches ErrInvalidInput
+type HookRejectedError struct {
+	Hook    string
+	Message string
+}
+
+// Error implements the error interface
+func (e *HookRejectedError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("change rejected by hook %s", e.Hook)
+	}
+	return fmt.Sprintf("change rejected by hook %s: %s", e.Hook, e.Message)
+}
+
+// Is allows errors.Is(err, ErrInvalidInput) to match
+func (e *HookRejectedError) Is(target error) bool {
+	return target == ErrInvalidInput
+}
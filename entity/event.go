@@ -0,0 +1,19 @@
+package entity
+
+// TaskEventType names a kind of change to a task
+type TaskEventType string
+
+const (
+	TaskAdded         TaskEventType = "add"
+	TaskModified      TaskEventType = "modify"
+	TaskStatusChanged TaskEventType = "status-change"
+	TaskDeleted       TaskEventType = "delete"
+)
+
+// TaskEvent describes a change to a task; Before is nil when the task is
+// added and After is nil when it is deleted
+type TaskEvent struct {
+	Type   TaskEventType `json:"event"`
+	Before *Task         `json:"before"`
+	After  *Task         `json:"after"`
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxEntry is a webhook delivery waiting to be made or retried
+type OutboxEntry struct {
+	// ID identifies the event; it is the same for every URL the event goes to
+	ID        string          `json:"id"`
+	URL       string          `json:"url"`
+	Event     TaskEventType   `json:"event"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"next_attempt"`
+	LastError   string    `json:"last_error,omitempty"`
+	// Failed is set once every retry was used up
+	Failed bool `json:"failed,omitempty"`
+	// ClaimedUntil keeps other processes from delivering the entry while one
+	// is posting it; a claim left by a process that exited simply expires
+	ClaimedUntil time.Time `json:"claimed_until,omitzero"`
+}
+
+// Outbox holds the webhook deliveries that have not succeeded yet
+type Outbox struct {
+	Entries []*OutboxEntry `json:"entries"`
+}
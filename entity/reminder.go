@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+)
+
+// ReminderKind tells whether a reminder is for a task coming due or one past due
+type ReminderKind string
+
+const (
+	ReminderUpcoming ReminderKind = "upcoming"
+	ReminderOverdue  ReminderKind = "overdue"
+)
+
+// Reminder is a notice about a task's due date
+type Reminder struct {
+	Kind ReminderKind `json:"kind"`
+	Task *Task        `json:"task"`
+}
+
+// ReminderState remembers which reminders were delivered, so that a restarted
+// watcher does not send them again
+type ReminderState struct {
+	// Sent maps a reminder key to when it was delivered
+	Sent map[string]time.Time `json:"sent"`
+}
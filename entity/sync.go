@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"time"
+)
+
+// SyncValue is a field value with the time it was written, compared with
+// last-writer-wins when devices sync
+type SyncValue struct {
+	Value string    `json:"value"`
+	At    time.Time `json:"at"`
+}
+
+// Newer reports whether v should replace current; ties go to the greater
+// value so that every replica picks the same winner
+func (v SyncValue) Newer(current SyncValue) bool {
+	if !v.At.Equal(current.At) {
+		return v.At.After(current.At)
+	}
+	return v.Value > current.Value
+}
+
+// SyncRecord is the sync server's copy of a task, with a timestamp per field
+type SyncRecord struct {
+	ID        int                  `json:"id"`
+	CreatedAt time.Time            `json:"created_at"`
+	Fields    map[string]SyncValue `json:"fields"`
+	// Rev is the server revision at which the record last changed
+	Rev int `json:"rev"`
+}
+
+// SyncServerState is everything the sync server stores
+type SyncServerState struct {
+	Revision int                 `json:"revision"`
+	Records  map[int]*SyncRecord `json:"records"`
+}
+
+// SyncState is what a device remembers about its last sync
+type SyncState struct {
+	// Revision is the last server revision pulled
+	Revision int `json:"revision"`
+	// Fields holds a hash of each synced field value per task ID, so local
+	// changes can be found without keeping a plaintext copy of the tasks
+	Fields map[int]map[string]string `json:"fields"`
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"slices"
 	"time"
 )
 
@@ -13,6 +14,12 @@ const (
 	TaskStatusDone       TaskStatus = "done"
 )
 
+// StatusChange records when a task entered a status
+type StatusChange struct {
+	Status TaskStatus `json:"status"`
+	At     time.Time  `json:"at"`
+}
+
 // Task represents a task entity
 type Task struct {
 	ID          int        `json:"id"`
@@ -21,8 +28,18 @@ type Task struct {
 	Status      TaskStatus `json:"status"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
+	// Due is when the task should be done, nil for no due date
+	Due *time.Time `json:"due,omitempty"`
+	// History lists the status changes in order, starting with the status the task was created in
+	History []StatusChange `json:"history,omitempty"`
+	// Version is incremented on every stored update and used to detect concurrent edits
+	Version int `json:"version"`
 }
 
+// AnyVersion is passed instead of a version to apply a change whatever the
+// stored version; stored versions start at 1
+const AnyVersion = -1
+
 // NewTask creates a new task with default values
 func NewTask(id int, title, description string) *Task {
 	now := time.Now()
@@ -33,7 +50,67 @@ func NewTask(id int, title, description string) *Task {
 		Status:      TaskStatusToDo,
 		CreatedAt:   now,
 		UpdatedAt:   now,
+		Version:     1,
+	}
+}
+
+// Clone returns a copy of the task that can be modified independently
+func (t *Task) Clone() *Task {
+	clone := *t
+	if t.Due != nil {
+		due := *t.Due
+		clone.Due = &due
+	}
+	clone.History = slices.Clone(t.History)
+	return &clone
+}
+
+// SetDue sets or, when due is nil, clears the due date
+func (t *Task) SetDue(due *time.Time) {
+	t.Due = due
+	t.UpdatedAt = time.Now()
+}
+
+// IsOverdue reports whether the task is unfinished past its due date
+func (t *Task) IsOverdue(now time.Time) bool {
+	return t.Due != nil && t.Status != TaskStatusDone && now.After(*t.Due)
+}
+
+// RecordStatusChange appends the task's status to its history when it
+// differs from the status of previous, or unconditionally for a new task
+// when previous is nil
+func (t *Task) RecordStatusChange(previous *Task) {
+	if previous == nil {
+		t.History = append(t.History, StatusChange{Status: t.Status, At: t.CreatedAt})
+		return
+	}
+	if previous.Status != t.Status {
+		t.History = append(t.History, StatusChange{Status: t.Status, At: t.UpdatedAt})
+	}
+}
+
+// StartedAt returns when the task first moved to in-progress
+func (t *Task) StartedAt() (time.Time, bool) {
+	for _, change := range t.History {
+		if change.Status == TaskStatusInProgress {
+			return change.At, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// CompletedAt returns when a done task was last marked done; tasks done
+// before their history was recorded fall back to their last update
+func (t *Task) CompletedAt() (time.Time, bool) {
+	if t.Status != TaskStatusDone {
+		return time.Time{}, false
+	}
+	for _, change := range slices.Backward(t.History) {
+		if change.Status == TaskStatusDone {
+			return change.At, true
+		}
 	}
+	return t.UpdatedAt, true
 }
 
 // UpdateStatus updates the task status and timestamp
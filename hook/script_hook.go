@@ -0,0 +1,136 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+)
+
+// scriptTimeout bounds how long a hook script may run before the change is rejected
+const scriptTimeout = 30 * time.Second
+
+// ScriptHook runs the executables in a hooks directory before every change.
+// A script named after the event, such as on-add, or starting with that name
+// and a dot, such as on-add.10-check, receives the event as JSON on stdin.
+// A non-zero exit vetoes the change with the script's output as the reason;
+// a task printed as JSON on stdout replaces the task to be stored
+type ScriptHook struct {
+	dir string
+}
+
+// NewScriptHook creates a hook running the scripts in dir; a missing directory means no hooks
+func NewScriptHook(dir string) *ScriptHook {
+	return &ScriptHook{dir: dir}
+}
+
+// BeforeChange implements usecase.TaskHook; scripts for the same event run in
+// name order, each seeing the task as rewritten by the ones before it
+func (h *ScriptHook) BeforeChange(event entity.TaskEvent) (*entity.Task, error) {
+	scripts, err := h.scripts(event.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	var rewritten *entity.Task
+	for _, script := range scripts {
+		task, err := h.run(script, event)
+		if err != nil {
+			return nil, err
+		}
+		if task != nil && event.After != nil {
+			rewritten, event.After = task, task
+		}
+	}
+	return rewritten, nil
+}
+
+// scripts lists the executables for an event, sorted by name
+func (h *ScriptHook) scripts(eventType entity.TaskEventType) ([]string, error) {
+	if h.dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(h.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hooks directory: %w", err)
+	}
+
+	name := "on-" + string(eventType)
+	var scripts []string
+	for _, entry := range entries {
+		if entry.Name() != name && !strings.HasPrefix(entry.Name(), name+".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		// Scripts that are not executable are skipped, which is how a hook is disabled
+		if runtime.GOOS != "windows" && info.Mode().Perm()&0111 == 0 {
+			continue
+		}
+		scripts = append(scripts, filepath.Join(h.dir, entry.Name()))
+	}
+	slices.Sort(scripts)
+	return scripts, nil
+}
+
+// run executes one script and returns the task it printed, if any
+func (h *ScriptHook) run(script string, event entity.TaskEvent) (*entity.Task, error) {
+	input, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode hook input: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), scriptTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, script)
+	cmd.Dir = h.dir
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(), "TASK_TRACKER_EVENT="+string(event.Type))
+
+	name := filepath.Base(script)
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("failed to run hook %s: %w", name, err)
+		}
+		if ctx.Err() != nil {
+			return nil, &entity.HookRejectedError{Hook: name, Message: fmt.Sprintf("timed out after %s", scriptTimeout)}
+		}
+		// The script explains a veto on stderr, or on stdout if stderr is empty
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = strings.TrimSpace(stdout.String())
+		}
+		return nil, &entity.HookRejectedError{Hook: name, Message: message}
+	}
+
+	output := bytes.TrimSpace(stdout.Bytes())
+	if len(output) == 0 || event.After == nil {
+		return nil, nil
+	}
+
+	var task entity.Task
+	if err := json.Unmarshal(output, &task); err != nil {
+		return nil, fmt.Errorf("%w: hook %s printed invalid task JSON: %v", entity.ErrInvalidInput, name, err)
+	}
+	return &task, nil
+}
@@ -0,0 +1,62 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+	"github.com/Illuminateee/task-tracker.git/repository"
+	"github.com/Illuminateee/task-tracker.git/usecase"
+)
+
+// MergeFiles merges the task files ours and theirs, which diverged from base,
+// and writes the result to output in the format of ours
+func MergeFiles(base, ours, theirs, output string, options repository.FileOptions) (*usecase.MergeResult, error) {
+	var lists [3][]*entity.Task
+	for i, path := range []string{base, ours, theirs} {
+		if _, err := os.Stat(path); err != nil {
+			return nil, &entity.StorageError{Op: "open " + path, Err: err}
+		}
+		tasks, err := repository.NewFileTaskRepository(path, options).GetAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		lists[i] = tasks
+	}
+
+	result := usecase.MergeTasks(lists[0], lists[1], lists[2])
+
+	oursRepo := repository.NewFileTaskRepository(ours, options)
+	format, err := oursRepo.Format()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ours, err)
+	}
+	outputRepo := oursRepo
+	if output != ours {
+		options.Format = format
+		outputRepo = repository.NewFileTaskRepository(output, options)
+	}
+
+	// Replace the contents of output in one write
+	err = outputRepo.WithTx(func(tx repository.TaskTx) error {
+		existing, err := tx.GetAll()
+		if err != nil {
+			return err
+		}
+		for _, task := range existing {
+			if err := tx.Delete(task.ID); err != nil {
+				return err
+			}
+		}
+		for _, task := range result.Tasks {
+			if err := tx.Create(task); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	return result, nil
+}
@@ -2,6 +2,8 @@ package manager
 
 import (
 	"fmt"
+	"iter"
+	"time"
 
 	"github.com/Illuminateee/task-tracker.git/entity"
 	"github.com/Illuminateee/task-tracker.git/repository"
@@ -10,19 +12,158 @@ import (
 
 // TaskManager coordinates task operations and manages dependencies
 type TaskManager struct {
-	taskUseCase *usecase.TaskUseCase
+	taskUseCase  *usecase.TaskUseCase
+	taskRepo     *repository.FileTaskRepository
+	dataFilePath string
+	webhooks     *usecase.WebhookOutbox
 }
 
-// NewTaskManager creates a new task manager with JSON storage
-func NewTaskManager(dataFilePath string) *TaskManager {
-	taskRepo := repository.NewJSONTaskRepository(dataFilePath)
+// NewTaskManager creates a new task manager with file storage
+func NewTaskManager(dataFilePath string, options repository.FileOptions) *TaskManager {
+	taskRepo := repository.NewFileTaskRepository(dataFilePath, options)
 	taskUseCase := usecase.NewTaskUseCase(taskRepo)
 
 	return &TaskManager{
-		taskUseCase: taskUseCase,
+		taskUseCase:  taskUseCase,
+		taskRepo:     taskRepo,
+		dataFilePath: dataFilePath,
 	}
 }
 
+// AddHook registers a hook consulted before every change to the tasks
+func (tm *TaskManager) AddHook(hook usecase.TaskHook) {
+	tm.taskUseCase.AddHook(hook)
+}
+
+// EnableWebhooks reports every change to urls through an outbox kept next to
+// the tasks file; onError receives delivery failures as they happen
+func (tm *TaskManager) EnableWebhooks(urls []string, post usecase.WebhookPost, onError func(error)) {
+	tm.webhooks = usecase.NewWebhookOutbox(tm.outboxStore(), urls, post, onError)
+	tm.taskUseCase.AddJournal(tm.webhooks)
+}
+
+// WaitForWebhooks waits up to timeout for deliveries started in the
+// background, reporting whether they finished
+func (tm *TaskManager) WaitForWebhooks(timeout time.Duration) bool {
+	if tm.webhooks == nil {
+		return true
+	}
+	return tm.webhooks.Wait(timeout)
+}
+
+// outboxStore returns the store of the webhook outbox kept next to the tasks file
+func (tm *TaskManager) outboxStore() *repository.FileOutboxStore {
+	return repository.NewFileOutboxStore(tm.dataFilePath+".outbox", tm.taskRepo)
+}
+
+// WebhooksEnabled reports whether changes are sent to webhooks
+func (tm *TaskManager) WebhooksEnabled() bool {
+	return tm.webhooks != nil
+}
+
+// FlushWebhooks delivers the queued webhook events that are due, or all of them
+func (tm *TaskManager) FlushWebhooks(all bool) (*usecase.WebhookFlushResult, error) {
+	return tm.webhooks.Flush(all)
+}
+
+// PendingWebhooks returns the webhook deliveries waiting in the outbox
+func (tm *TaskManager) PendingWebhooks() ([]*entity.OutboxEntry, error) {
+	return tm.webhooks.Entries()
+}
+
+// DropFailedWebhooks discards the webhook deliveries that used up their retries
+func (tm *TaskManager) DropFailedWebhooks() (int, error) {
+	return tm.webhooks.DropFailed()
+}
+
+// Sync exchanges changes with a sync server; the sync state is kept next to
+// the tasks file and only saved once the server's changes are applied
+func (tm *TaskManager) Sync(exchange usecase.SyncExchange) (*usecase.SyncResult, error) {
+	statePath := tm.dataFilePath + ".sync"
+	state, err := repository.LoadSyncState(statePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sync state: %w", err)
+	}
+
+	result, err := tm.taskUseCase.Sync(state, exchange)
+	if err != nil {
+		return nil, err
+	}
+	if err := repository.SaveSyncState(statePath, state); err != nil {
+		return nil, fmt.Errorf("failed to save sync state: %w", err)
+	}
+	return result, nil
+}
+
+// Stats computes the task statistics for the last weeks weeks, listing up to oldest open tasks
+func (tm *TaskManager) Stats(weeks, oldest int) (*usecase.TaskStats, error) {
+	return tm.taskUseCase.Stats(time.Now(), weeks, oldest)
+}
+
+// Report summarizes the work between since and until
+func (tm *TaskManager) Report(since, until time.Time) (*usecase.TaskReport, error) {
+	return tm.taskUseCase.Report(since, until)
+}
+
+// SendReminders delivers the reminders due at now through sinks; which
+// reminders were sent is kept next to the tasks file across restarts
+func (tm *TaskManager) SendReminders(sinks []usecase.ReminderSink, now time.Time, lead time.Duration) (int, error) {
+	statePath := tm.dataFilePath + ".reminders"
+	state, err := repository.LoadReminderState(statePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load reminder state: %w", err)
+	}
+
+	// Deliveries are recorded even when some sinks failed
+	sent, sendErr := tm.taskUseCase.SendReminders(state, sinks, now, lead)
+	if err := repository.SaveReminderState(statePath, state); err != nil {
+		return sent, fmt.Errorf("failed to save reminder state: %w", err)
+	}
+	return sent, sendErr
+}
+
+// StorageFormat returns the format of the tasks file
+func (tm *TaskManager) StorageFormat() (repository.Format, error) {
+	return tm.taskRepo.Format()
+}
+
+// ConvertStorage rewrites the tasks file in the named format
+func (tm *TaskManager) ConvertStorage(format string) error {
+	target, err := repository.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	return tm.taskRepo.Convert(target)
+}
+
+// StorageEncryption describes how the tasks file is encrypted
+func (tm *TaskManager) StorageEncryption() (repository.EncryptionInfo, error) {
+	return tm.taskRepo.Encryption()
+}
+
+// EncryptStorage seals the tasks file with a key derived from secret
+func (tm *TaskManager) EncryptStorage(secret repository.Secret) error {
+	return tm.reseal(func() error { return tm.taskRepo.Encrypt(secret) })
+}
+
+// DecryptStorage rewrites the encrypted tasks file as plaintext
+func (tm *TaskManager) DecryptStorage() error {
+	return tm.reseal(tm.taskRepo.Decrypt)
+}
+
+// RekeyStorage re-seals the encrypted tasks file with a key derived from secret
+func (tm *TaskManager) RekeyStorage(secret repository.Secret) error {
+	return tm.reseal(func() error { return tm.taskRepo.Rekey(secret) })
+}
+
+// reseal runs a change to the encryption of the tasks file, re-sealing the
+// webhook outbox, which shares its key, to match
+func (tm *TaskManager) reseal(change func() error) error {
+	return tm.outboxStore().Update(func(*entity.Outbox) error {
+		return change()
+	})
+}
+
 // AddTask adds a new task
 func (tm *TaskManager) AddTask(title, description string) (*entity.Task, error) {
 	return tm.taskUseCase.CreateTask(title, description)
@@ -33,11 +174,26 @@ func (tm *TaskManager) UpdateTask(id int, title, description string) (*entity.Ta
 	return tm.taskUseCase.UpdateTask(id, title, description)
 }
 
+// UpdateTaskAtVersion updates a task only if it is still at the given version
+func (tm *TaskManager) UpdateTaskAtVersion(id, version int, title, description string) (*entity.Task, error) {
+	return tm.taskUseCase.UpdateTaskAtVersion(id, version, title, description)
+}
+
+// SetDue sets the due date of a task, or clears it when due is nil
+func (tm *TaskManager) SetDue(id int, due *time.Time) (*entity.Task, error) {
+	return tm.taskUseCase.SetTaskDue(id, due)
+}
+
 // DeleteTask deletes a task
 func (tm *TaskManager) DeleteTask(id int) error {
 	return tm.taskUseCase.DeleteTask(id)
 }
 
+// DeleteTasks deletes several tasks at once
+func (tm *TaskManager) DeleteTasks(ids []int) error {
+	return tm.taskUseCase.DeleteTasks(ids)
+}
+
 // GetTask gets a specific task by ID
 func (tm *TaskManager) GetTask(id int) (*entity.Task, error) {
 	return tm.taskUseCase.GetTask(id)
@@ -63,11 +219,65 @@ func (tm *TaskManager) ListInProgressTasks() ([]*entity.Task, error) {
 	return tm.taskUseCase.GetTasksByStatus(entity.TaskStatusInProgress)
 }
 
+// ListTasksDueBetween returns the unfinished tasks due in [from, to), sorted by due date
+func (tm *TaskManager) ListTasksDueBetween(from, to time.Time) ([]*entity.Task, error) {
+	return tm.taskUseCase.GetTasksDueBetween(from, to)
+}
+
 // ListPendingTasks returns all non-done tasks (todo + in-progress)
 func (tm *TaskManager) ListPendingTasks() ([]*entity.Task, error) {
 	return tm.taskUseCase.GetPendingTasks()
 }
 
+// ListTasks returns tasks matching a named filter (all, done, todo, in-progress, pending)
+func (tm *TaskManager) ListTasks(filter string) ([]*entity.Task, error) {
+	switch filter {
+	case "", "all":
+		return tm.ListAllTasks()
+	case "done":
+		return tm.ListDoneTasks()
+	case "todo":
+		return tm.ListTodoTasks()
+	case "in-progress":
+		return tm.ListInProgressTasks()
+	case "pending":
+		return tm.ListPendingTasks()
+	default:
+		return nil, invalidFilterError(filter)
+	}
+}
+
+// ListTasksPage returns a page of tasks matching a named filter; pages are
+// numbered from 1 and a limit of zero returns every task
+func (tm *TaskManager) ListTasksPage(filter string, page, limit int) (*repository.TaskPage, error) {
+	if page < 1 {
+		return nil, fmt.Errorf("%w: page must be at least 1", entity.ErrInvalidInput)
+	}
+
+	var statuses []entity.TaskStatus
+	switch filter {
+	case "", "all":
+	case "done", "todo", "in-progress":
+		statuses = []entity.TaskStatus{entity.TaskStatus(filter)}
+	case "pending":
+		statuses = []entity.TaskStatus{entity.TaskStatusToDo, entity.TaskStatusInProgress}
+	default:
+		return nil, invalidFilterError(filter)
+	}
+
+	return tm.taskUseCase.ListTasksPage(statuses, (page-1)*limit, limit)
+}
+
+// IterateTasks iterates over all tasks in ID order without loading them all at once
+func (tm *TaskManager) IterateTasks() iter.Seq2[*entity.Task, error] {
+	return tm.taskUseCase.IterateTasks()
+}
+
+// invalidFilterError reports an unknown list filter
+func invalidFilterError(filter string) error {
+	return fmt.Errorf("%w: invalid filter: %s. Valid filters: all, done, todo, in-progress, pending", entity.ErrInvalidInput, filter)
+}
+
 // MarkDone marks a task as completed
 func (tm *TaskManager) MarkDone(id int) (*entity.Task, error) {
 	return tm.taskUseCase.MarkTaskDone(id)
@@ -85,10 +295,25 @@ func (tm *TaskManager) MarkTodo(id int) (*entity.Task, error) {
 
 // UpdateTaskStatus updates task status using string value
 func (tm *TaskManager) UpdateTaskStatus(id int, statusStr string) (*entity.Task, error) {
+	return tm.UpdateTaskStatusAtVersion(id, entity.AnyVersion, statusStr)
+}
+
+// UpdateTaskStatusAtVersion updates task status only if the task is still at
+// the given version; entity.AnyVersion accepts any version
+func (tm *TaskManager) UpdateTaskStatusAtVersion(id, version int, statusStr string) (*entity.Task, error) {
 	if !entity.IsValidStatus(statusStr) {
-		return nil, fmt.Errorf("invalid status '%s'. Valid statuses are: todo, in-progress, done", statusStr)
+		return nil, fmt.Errorf("%w: invalid status '%s'. Valid statuses are: todo, in-progress, done", entity.ErrInvalidInput, statusStr)
 	}
 
 	status := entity.TaskStatus(statusStr)
-	return tm.taskUseCase.UpdateTaskStatus(id, status)
+	return tm.taskUseCase.UpdateTaskStatusAtVersion(id, version, status)
+}
+
+// UpdateTasksStatus updates the status of several tasks at once using a string value
+func (tm *TaskManager) UpdateTasksStatus(ids []int, statusStr string) ([]*entity.Task, error) {
+	if !entity.IsValidStatus(statusStr) {
+		return nil, fmt.Errorf("%w: invalid status '%s'. Valid statuses are: todo, in-progress, done", entity.ErrInvalidInput, statusStr)
+	}
+
+	return tm.taskUseCase.UpdateTasksStatus(ids, entity.TaskStatus(statusStr))
 }
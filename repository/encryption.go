@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+)
+
+// SecretKind says how a secret is turned into an encryption key
+type SecretKind byte
+
+// Supported secret kinds
+const (
+	// SecretPassphrase is stretched with PBKDF2-SHA256
+	SecretPassphrase SecretKind = 1
+	// SecretKeyFile is random key material expanded with HKDF-SHA256
+	SecretKeyFile SecretKind = 2
+)
+
+// String returns a human-readable name for the secret kind
+func (k SecretKind) String() string {
+	switch k {
+	case SecretPassphrase:
+		return "passphrase"
+	case SecretKeyFile:
+		return "key file"
+	default:
+		return fmt.Sprintf("unknown (%d)", byte(k))
+	}
+}
+
+// Secret is a passphrase or key file contents that an encrypted file is sealed with
+type Secret struct {
+	Kind  SecretKind
+	Value []byte
+}
+
+// EncryptionInfo describes how the tasks file is encrypted
+type EncryptionInfo struct {
+	Encrypted bool
+	Kind      SecretKind
+	// Iterations is the PBKDF2 work factor for passphrase-sealed files
+	Iterations int
+}
+
+// Encrypted file layout: magic, secret kind (1 byte), PBKDF2 iterations
+// (4 bytes, big endian), salt, nonce, then the AES-256-GCM ciphertext of the
+// JSON or binary payload. Everything before the nonce is authenticated.
+var encryptedMagic = []byte("TTENC1\n")
+
+const (
+	// pbkdf2Iterations is the work factor used when sealing with a new passphrase
+	pbkdf2Iterations = 600_000
+	// maxPBKDF2Iterations bounds the work factor read from a file header, so a
+	// crafted file cannot stall every open
+	maxPBKDF2Iterations = 10 * pbkdf2Iterations
+	// minKeyFileSize is the least key material accepted from a key file
+	minKeyFileSize = 16
+
+	saltSize   = 16
+	keySize    = 32
+	headerSize = 7 + 1 + 4 + saltSize
+)
+
+// ErrWrongSecret is returned when an encrypted file cannot be opened with the given secret
+var ErrWrongSecret = errors.New("wrong passphrase or key, or the file is corrupt")
+
+// sealing holds the parameters and derived key of an encrypted file, reused to
+// re-seal it on every write
+type sealing struct {
+	kind       SecretKind
+	iterations uint32
+	salt       []byte
+	key        []byte
+}
+
+// newSealing derives a key for secret with a fresh salt
+func newSealing(secret Secret) (*sealing, error) {
+	if len(secret.Value) == 0 {
+		return nil, fmt.Errorf("%w: empty %s", entity.ErrInvalidInput, secret.Kind)
+	}
+	if secret.Kind == SecretKeyFile && len(secret.Value) < minKeyFileSize {
+		return nil, fmt.Errorf("%w: key file must contain at least %d bytes", entity.ErrInvalidInput, minKeyFileSize)
+	}
+
+	s := &sealing{kind: secret.Kind, salt: make([]byte, saltSize)}
+	if secret.Kind == SecretPassphrase {
+		s.iterations = pbkdf2Iterations
+	}
+	if _, err := rand.Read(s.salt); err != nil {
+		return nil, err
+	}
+
+	key, err := deriveKey(secret, s.iterations, s.salt)
+	if err != nil {
+		return nil, err
+	}
+	s.key = key
+	return s, nil
+}
+
+// info describes the sealing for display
+func (s *sealing) info() EncryptionInfo {
+	if s == nil {
+		return EncryptionInfo{}
+	}
+	return EncryptionInfo{Encrypted: true, Kind: s.kind, Iterations: int(s.iterations)}
+}
+
+// header returns the authenticated file header
+func (s *sealing) header() []byte {
+	header := make([]byte, 0, headerSize)
+	header = append(header, encryptedMagic...)
+	header = append(header, byte(s.kind))
+	header = binary.BigEndian.AppendUint32(header, s.iterations)
+	return append(header, s.salt...)
+}
+
+// seal encrypts plaintext with a fresh nonce
+func (s *sealing) seal(plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(s.key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	header := s.header()
+	out := append(header, nonce...)
+	return gcm.Seal(out, nonce, plaintext, header), nil
+}
+
+// isEncrypted reports whether data is an encrypted tasks file
+func isEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedMagic)
+}
+
+// openSealed decrypts an encrypted tasks file. The key of previous is reused
+// when the file was sealed with the same parameters; otherwise the secret is
+// requested from secretFn
+func openSealed(data []byte, previous *sealing, secretFn func() (Secret, error)) ([]byte, *sealing, error) {
+	if len(data) < headerSize {
+		return nil, nil, errors.New("truncated encrypted file")
+	}
+	header := data[:headerSize]
+	s := &sealing{
+		kind:       SecretKind(header[len(encryptedMagic)]),
+		iterations: binary.BigEndian.Uint32(header[len(encryptedMagic)+1:]),
+		salt:       bytes.Clone(header[headerSize-saltSize:]),
+	}
+	if s.kind == SecretPassphrase && s.iterations > maxPBKDF2Iterations {
+		return nil, nil, fmt.Errorf("PBKDF2 iteration count %d exceeds the maximum of %d", s.iterations, maxPBKDF2Iterations)
+	}
+
+	if previous != nil && previous.kind == s.kind && previous.iterations == s.iterations && bytes.Equal(previous.salt, s.salt) {
+		s.key = previous.key
+	} else {
+		if secretFn == nil {
+			return nil, nil, fmt.Errorf("tasks file is encrypted with a %s, but none is configured", s.kind)
+		}
+		secret, err := secretFn()
+		if err != nil {
+			return nil, nil, err
+		}
+		if secret.Kind != s.kind {
+			return nil, nil, fmt.Errorf("tasks file is encrypted with a %s, but a %s was given", s.kind, secret.Kind)
+		}
+		if s.key, err = deriveKey(secret, s.iterations, s.salt); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	gcm, err := newGCM(s.key)
+	if err != nil {
+		return nil, nil, err
+	}
+	rest := data[headerSize:]
+	if len(rest) < gcm.NonceSize() {
+		return nil, nil, errors.New("truncated encrypted file")
+	}
+	plaintext, err := gcm.Open(nil, rest[:gcm.NonceSize()], rest[gcm.NonceSize():], header)
+	if err != nil {
+		return nil, nil, ErrWrongSecret
+	}
+	return plaintext, s, nil
+}
+
+// deriveKey turns secret into an AES-256 key
+func deriveKey(secret Secret, iterations uint32, salt []byte) ([]byte, error) {
+	switch secret.Kind {
+	case SecretPassphrase:
+		if iterations == 0 {
+			return nil, errors.New("invalid PBKDF2 iteration count")
+		}
+		return pbkdf2.Key(sha256.New, string(secret.Value), salt, int(iterations), keySize)
+	case SecretKeyFile:
+		return hkdf.Key(sha256.New, secret.Value, salt, "task-tracker tasks file", keySize)
+	default:
+		return nil, fmt.Errorf("unsupported secret kind %d", byte(secret.Kind))
+	}
+}
+
+// newGCM creates an AES-GCM cipher for key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
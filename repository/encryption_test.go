@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestOpenSealedRejectsExcessiveIterations(t *testing.T) {
+	data := append([]byte(nil), encryptedMagic...)
+	data = append(data, byte(SecretPassphrase))
+	data = binary.BigEndian.AppendUint32(data, maxPBKDF2Iterations+1)
+	data = append(data, make([]byte, saltSize+64)...)
+
+	secretFn := func() (Secret, error) {
+		t.Fatal("secret requested for a header over the iteration limit")
+		return Secret{}, nil
+	}
+	_, _, err := openSealed(data, nil, secretFn)
+	if err == nil || !strings.Contains(err.Error(), "iteration count") {
+		t.Fatalf("openSealed error = %v, want an iteration count error", err)
+	}
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+)
+
+// Lock files are only held while a small file is read, changed and written
+// back, so one older than lockStaleAfter was left behind by a crashed process
+const (
+	lockRetryInterval = 10 * time.Millisecond
+	lockTimeout       = time.Minute
+	lockStaleAfter    = 30 * time.Second
+)
+
+// lockFile takes an exclusive lock on path, shared by every process, by
+// creating path.lock; the returned function releases it
+func lockFile(path string) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, &entity.StorageError{Op: "lock " + path, Err: err}
+		}
+
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > lockStaleAfter {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, &entity.StorageError{Op: "lock " + path, Err: fmt.Errorf("%s is held by another process", lockPath)}
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
@@ -0,0 +1,556 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"iter"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+)
+
+// racyWindow is how soon after its last modification a file may be read before
+// its size and mtime stop being trusted; coarse mtime resolution on some
+// filesystems could otherwise hide a same-sized write
+const racyWindow = 2 * time.Second
+
+// FileTaskRepository implements TaskRepository using a single JSON or binary
+// file, optionally encrypted. Parsed tasks are kept in memory, indexed by ID and status, and are only
+// reloaded when the file changes on disk
+type FileTaskRepository struct {
+	filePath string
+	options  FileOptions
+
+	// mu guards cache; a cache is never modified once built, only replaced
+	mu    sync.Mutex
+	cache *taskCache
+}
+
+// taskCache is an immutable snapshot of the tasks file
+type taskCache struct {
+	stamp   fileStamp
+	format  Format
+	sealing *sealing
+	// tasks is sorted by ID
+	tasks    []*entity.Task
+	byID     map[int]*entity.Task
+	byStatus map[entity.TaskStatus][]*entity.Task
+	maxID    int
+}
+
+// fileStamp identifies the contents of the tasks file
+type fileStamp struct {
+	exists   bool
+	size     int64
+	modTime  time.Time
+	checksum [sha256.Size]byte
+	readAt   time.Time
+}
+
+// NewFileTaskRepository creates a new file task repository
+func NewFileTaskRepository(filePath string, options FileOptions) *FileTaskRepository {
+	if options.Format == "" {
+		options.Format = FormatJSON
+	}
+	return &FileTaskRepository{
+		filePath: filePath,
+		options:  options,
+	}
+}
+
+// Create adds a new task to the file
+func (r *FileTaskRepository) Create(task *entity.Task) error {
+	return r.WithTx(func(tx TaskTx) error {
+		return tx.Create(task)
+	})
+}
+
+// GetByID retrieves a task by its ID
+func (r *FileTaskRepository) GetByID(id int) (*entity.Task, error) {
+	cache, err := r.snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	task, ok := cache.byID[id]
+	if !ok {
+		return nil, &entity.TaskNotFoundError{ID: id}
+	}
+	return task.Clone(), nil
+}
+
+// GetAll retrieves all tasks sorted by ID
+func (r *FileTaskRepository) GetAll() ([]*entity.Task, error) {
+	cache, err := r.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return cloneTasks(cache.tasks), nil
+}
+
+// GetByStatus retrieves tasks filtered by status, sorted by ID
+func (r *FileTaskRepository) GetByStatus(status entity.TaskStatus) ([]*entity.Task, error) {
+	cache, err := r.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return cloneTasks(cache.byStatus[status]), nil
+}
+
+// All iterates over copies of the cached tasks in ID order
+func (r *FileTaskRepository) All() iter.Seq2[*entity.Task, error] {
+	return func(yield func(*entity.Task, error) bool) {
+		cache, err := r.snapshot()
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		for _, task := range cache.tasks {
+			if !yield(task.Clone(), nil) {
+				return
+			}
+		}
+	}
+}
+
+// List returns a page of tasks, copying only the tasks on the page
+func (r *FileTaskRepository) List(opts ListOptions) (*TaskPage, error) {
+	cache, err := r.snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	// A single status can be served from its index
+	source := cache.tasks
+	if len(opts.Statuses) == 1 {
+		source = cache.byStatus[opts.Statuses[0]]
+	}
+
+	page := &TaskPage{Offset: opts.Offset, Limit: opts.Limit}
+	for _, task := range source {
+		if !opts.Matches(task) {
+			continue
+		}
+
+		if page.Total >= opts.Offset && (opts.Limit <= 0 || len(page.Tasks) < opts.Limit) {
+			page.Tasks = append(page.Tasks, task.Clone())
+		}
+		page.Total++
+	}
+	return page, nil
+}
+
+// Update modifies an existing task
+func (r *FileTaskRepository) Update(updatedTask *entity.Task) error {
+	return r.WithTx(func(tx TaskTx) error {
+		return tx.Update(updatedTask)
+	})
+}
+
+// Delete removes a task by ID
+func (r *FileTaskRepository) Delete(id int) error {
+	return r.WithTx(func(tx TaskTx) error {
+		return tx.Delete(id)
+	})
+}
+
+// GetNextID returns the next available ID
+func (r *FileTaskRepository) GetNextID() (int, error) {
+	cache, err := r.snapshot()
+	if err != nil {
+		return 0, err
+	}
+	return cache.maxID + 1, nil
+}
+
+// WithTx runs fn against the current tasks and saves them once if fn succeeds;
+// if fn fails the file is left untouched. The tasks file is locked against
+// other processes and the repository against other goroutines while fn runs,
+// so fn must only use tx
+func (r *FileTaskRepository) WithTx(fn func(tx TaskTx) error) error {
+	unlock, err := r.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	cache, err := r.snapshotLocked()
+	if err != nil {
+		return err
+	}
+
+	tx := &jsonTaskTx{tasks: maps.Clone(cache.byID), maxID: cache.maxID}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if !tx.dirty {
+		return nil
+	}
+	return r.saveTasks(slices.Collect(maps.Values(tx.tasks)), cache.format, cache.sealing)
+}
+
+// Format returns the format of the tasks file, or the configured format for a
+// file that does not exist yet
+func (r *FileTaskRepository) Format() (Format, error) {
+	cache, err := r.snapshot()
+	if err != nil {
+		return "", err
+	}
+	return cache.format, nil
+}
+
+// Convert rewrites the tasks file in the given format
+func (r *FileTaskRepository) Convert(format Format) error {
+	unlock, err := r.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	cache, err := r.snapshotLocked()
+	if err != nil {
+		return err
+	}
+	if err := r.saveTasks(slices.Clone(cache.tasks), format, cache.sealing); err != nil {
+		return err
+	}
+	// Keep the format for a file recreated after being deleted
+	r.options.Format = format
+	return nil
+}
+
+// Encryption describes how the tasks file is encrypted
+func (r *FileTaskRepository) Encryption() (EncryptionInfo, error) {
+	cache, err := r.snapshot()
+	if err != nil {
+		return EncryptionInfo{}, err
+	}
+	return cache.sealing.info(), nil
+}
+
+// Encrypt seals a plaintext tasks file with a key derived from secret
+func (r *FileTaskRepository) Encrypt(secret Secret) error {
+	return r.reseal(false, &secret)
+}
+
+// Decrypt rewrites an encrypted tasks file as plaintext
+func (r *FileTaskRepository) Decrypt() error {
+	return r.reseal(true, nil)
+}
+
+// Rekey re-seals an encrypted tasks file with a key derived from secret
+func (r *FileTaskRepository) Rekey(secret Secret) error {
+	return r.reseal(true, &secret)
+}
+
+// reseal rewrites the tasks file sealed with secret, or as plaintext when
+// secret is nil, after checking whether it is currently encrypted
+func (r *FileTaskRepository) reseal(encrypted bool, secret *Secret) error {
+	unlock, err := r.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	cache, err := r.snapshotLocked()
+	if err != nil {
+		return err
+	}
+	if encrypted && cache.sealing == nil {
+		return fmt.Errorf("%w: tasks file is not encrypted", entity.ErrInvalidInput)
+	}
+	if !encrypted && cache.sealing != nil {
+		return fmt.Errorf("%w: tasks file is already encrypted", entity.ErrInvalidInput)
+	}
+
+	var s *sealing
+	if secret != nil {
+		if s, err = newSealing(*secret); err != nil {
+			return err
+		}
+	}
+	return r.saveTasks(slices.Clone(cache.tasks), cache.format, s)
+}
+
+// jsonTaskTx is an in-memory unit of work over the cached tasks; it never
+// modifies cached tasks, only replaces them with copies
+type jsonTaskTx struct {
+	tasks map[int]*entity.Task
+	maxID int
+	dirty bool
+}
+
+// GetByID returns a copy of the task with the given ID
+func (tx *jsonTaskTx) GetByID(id int) (*entity.Task, error) {
+	task, ok := tx.tasks[id]
+	if !ok {
+		return nil, &entity.TaskNotFoundError{ID: id}
+	}
+	return task.Clone(), nil
+}
+
+// GetAll returns copies of all tasks sorted by ID
+func (tx *jsonTaskTx) GetAll() ([]*entity.Task, error) {
+	tasks := make([]*entity.Task, 0, len(tx.tasks))
+	for _, task := range tx.tasks {
+		tasks = append(tasks, task.Clone())
+	}
+	sortByID(tasks)
+	return tasks, nil
+}
+
+// Create adds a new task
+func (tx *jsonTaskTx) Create(task *entity.Task) error {
+	if _, ok := tx.tasks[task.ID]; ok {
+		return &entity.ConflictError{ID: task.ID, Reason: "already exists"}
+	}
+
+	tx.tasks[task.ID] = task.Clone()
+	tx.maxID = max(tx.maxID, task.ID)
+	tx.dirty = true
+	return nil
+}
+
+// Update replaces an existing task after checking its version, which catches
+// tasks read before the transaction began, as FileTaskRepository.Update does
+func (tx *jsonTaskTx) Update(updatedTask *entity.Task) error {
+	stored, ok := tx.tasks[updatedTask.ID]
+	if !ok {
+		return &entity.TaskNotFoundError{ID: updatedTask.ID}
+	}
+
+	if stored.Version != updatedTask.Version {
+		return entity.NewVersionConflictError(stored.ID, stored.Version, updatedTask.Version)
+	}
+
+	updatedTask.Version = stored.Version + 1
+	tx.tasks[updatedTask.ID] = updatedTask.Clone()
+	tx.dirty = true
+	return nil
+}
+
+// Delete removes a task by ID
+func (tx *jsonTaskTx) Delete(id int) error {
+	if _, ok := tx.tasks[id]; !ok {
+		return &entity.TaskNotFoundError{ID: id}
+	}
+
+	delete(tx.tasks, id)
+	if id == tx.maxID {
+		// The highest ID is free again, as it was before the cache existed
+		tx.maxID = 0
+		for taskID := range tx.tasks {
+			tx.maxID = max(tx.maxID, taskID)
+		}
+	}
+	tx.dirty = true
+	return nil
+}
+
+// NextID returns the next available ID, accounting for tasks created in this transaction
+func (tx *jsonTaskTx) NextID() (int, error) {
+	return tx.maxID + 1, nil
+}
+
+// lock takes mu and the lock file shared with other processes writing the
+// tasks file, so that a read, change and write is never interleaved with
+// another; the returned function releases both
+func (r *FileTaskRepository) lock() (func(), error) {
+	r.mu.Lock()
+	unlock, err := lockFile(r.filePath)
+	if err != nil {
+		r.mu.Unlock()
+		return nil, err
+	}
+	return func() {
+		unlock()
+		r.mu.Unlock()
+	}, nil
+}
+
+// snapshot returns the cached tasks, reloading them if the file has changed
+func (r *FileTaskRepository) snapshot() (*taskCache, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.snapshotLocked()
+}
+
+// snapshotLocked is snapshot for callers already holding mu
+func (r *FileTaskRepository) snapshotLocked() (*taskCache, error) {
+	info, err := os.Stat(r.filePath)
+	if os.IsNotExist(err) {
+		// File doesn't exist, there are no tasks
+		if r.cache == nil || r.cache.stamp.exists {
+			r.cache = newTaskCache(fileStamp{}, r.options.Format, nil, nil)
+		}
+		return r.cache, nil
+	}
+	if err != nil {
+		return nil, &entity.StorageError{Op: "read tasks file", Err: err}
+	}
+
+	if r.cache != nil && r.cache.stamp.matches(info) {
+		return r.cache, nil
+	}
+
+	data, err := os.ReadFile(r.filePath)
+	if err != nil {
+		return nil, &entity.StorageError{Op: "read tasks file", Err: err}
+	}
+	stamp := newFileStamp(info, data)
+
+	// The file was touched or rewritten with the same contents
+	if r.cache != nil && r.cache.stamp.exists && r.cache.stamp.checksum == stamp.checksum {
+		cache := *r.cache
+		cache.stamp = stamp
+		r.cache = &cache
+		return r.cache, nil
+	}
+
+	var sealed *sealing
+	if isEncrypted(data) {
+		var previous *sealing
+		if r.cache != nil {
+			previous = r.cache.sealing
+		}
+		if data, sealed, err = openSealed(data, previous, r.options.Secret); err != nil {
+			return nil, &entity.StorageError{Op: "decrypt tasks file", Err: err}
+		}
+	}
+
+	tasks, format, err := decodeTasks(data)
+	if err != nil {
+		return nil, &entity.StorageError{Op: "unmarshal tasks", Err: err}
+	}
+
+	r.cache = newTaskCache(stamp, format, sealed, tasks)
+	return r.cache, nil
+}
+
+// saveTasks saves tasks to the file in ID order, sealed when sealed is not
+// nil, and makes them the cache
+func (r *FileTaskRepository) saveTasks(tasks []*entity.Task, format Format, sealed *sealing) error {
+	sortByID(tasks)
+
+	data, err := encodeTasks(tasks, format)
+	if err != nil {
+		return &entity.StorageError{Op: "marshal tasks", Err: err}
+	}
+	if sealed != nil {
+		if data, err = sealed.seal(data); err != nil {
+			return &entity.StorageError{Op: "encrypt tasks file", Err: err}
+		}
+	}
+
+	// Keep the file's permissions, but never leave an encrypted store readable by others
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(r.filePath); err == nil {
+		perm = info.Mode().Perm()
+	}
+	if sealed != nil {
+		perm &= 0600
+	}
+	if err := writeFileAtomic(r.filePath, data, perm); err != nil {
+		r.cache = nil
+		return &entity.StorageError{Op: "write tasks file", Err: err}
+	}
+
+	info, err := os.Stat(r.filePath)
+	if err != nil {
+		r.cache = nil
+		return nil
+	}
+	r.cache = newTaskCache(newFileStamp(info, data), format, sealed, tasks)
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path, syncs it and
+// renames it over path, so a crash never leaves path partially written
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// newFileStamp records the identity of data, which was read from the file described by info
+func newFileStamp(info os.FileInfo, data []byte) fileStamp {
+	return fileStamp{
+		exists:   true,
+		size:     int64(len(data)),
+		modTime:  info.ModTime(),
+		checksum: sha256.Sum256(data),
+		readAt:   time.Now(),
+	}
+}
+
+// matches reports whether info still describes the stamped contents without
+// reading the file; files read shortly after being modified are always re-read
+func (s fileStamp) matches(info os.FileInfo) bool {
+	return s.exists &&
+		s.size == info.Size() &&
+		s.modTime.Equal(info.ModTime()) &&
+		s.readAt.Sub(s.modTime) > racyWindow
+}
+
+// newTaskCache sorts tasks by ID and indexes them
+func newTaskCache(stamp fileStamp, format Format, sealed *sealing, tasks []*entity.Task) *taskCache {
+	sortByID(tasks)
+
+	cache := &taskCache{
+		stamp:    stamp,
+		format:   format,
+		sealing:  sealed,
+		tasks:    tasks,
+		byID:     make(map[int]*entity.Task, len(tasks)),
+		byStatus: make(map[entity.TaskStatus][]*entity.Task),
+	}
+	for _, task := range tasks {
+		cache.byID[task.ID] = task
+		cache.byStatus[task.Status] = append(cache.byStatus[task.Status], task)
+		cache.maxID = max(cache.maxID, task.ID)
+	}
+	return cache
+}
+
+// cloneTasks returns copies of tasks so callers cannot modify the cache
+func cloneTasks(tasks []*entity.Task) []*entity.Task {
+	clones := make([]*entity.Task, len(tasks))
+	for i, task := range tasks {
+		clones[i] = task.Clone()
+	}
+	return clones
+}
+
+// sortByID sorts tasks by ID for consistent ordering
+func sortByID(tasks []*entity.Task) {
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].ID < tasks[j].ID
+	})
+}
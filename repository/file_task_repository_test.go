@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+)
+
+func TestCacheInvalidatedBySameSizeWriteWithinMtimeTick(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	r := NewFileTaskRepository(path, FileOptions{})
+	if err := r.Create(entity.NewTask(1, "aaaa", "")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GetByID(1); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process rewrites the title in place, keeping the size, and the
+	// write lands within the same mtime tick as ours
+	changed := bytes.Replace(data, []byte(`"aaaa"`), []byte(`"bbbb"`), 1)
+	if bytes.Equal(changed, data) {
+		t.Fatal("stored file does not contain the task title")
+	}
+	if err := os.WriteFile(path, changed, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+
+	task, err := r.GetByID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Title != "bbbb" {
+		t.Errorf("title = %q after external write, want %q", task.Title, "bbbb")
+	}
+}
+
+func TestCacheKeptWhenFileIsRewrittenUnchanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	r := NewFileTaskRepository(path, FileOptions{})
+	if err := r.Create(entity.NewTask(1, "aaaa", "")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := r.snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Touching the file forces a re-read, but identical contents keep the parsed tasks
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := r.snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if &after.tasks[0] != &before.tasks[0] {
+		t.Error("cache was rebuilt although the file contents did not change")
+	}
+}
+
+func TestRepositoriesSharingAFileKeepEachOthersWrites(t *testing.T) {
+	// Each repository stands for a separate process with its own cache and mutex
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	repos := []*FileTaskRepository{
+		NewFileTaskRepository(path, FileOptions{}),
+		NewFileTaskRepository(path, FileOptions{}),
+	}
+
+	const perRepo = 25
+	var wg sync.WaitGroup
+	for _, r := range repos {
+		wg.Go(func() {
+			for i := range perRepo {
+				err := r.WithTx(func(tx TaskTx) error {
+					id, err := tx.NextID()
+					if err != nil {
+						return err
+					}
+					return tx.Create(entity.NewTask(id, fmt.Sprintf("Task %d", i), ""))
+				})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	tasks, err := NewFileTaskRepository(path, FileOptions{}).GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 2*perRepo {
+		t.Errorf("file holds %d tasks, want %d", len(tasks), 2*perRepo)
+	}
+	for i, task := range tasks {
+		if task.ID != i+1 {
+			t.Errorf("task %d has ID %d, want IDs 1 to %d without gaps or duplicates", i, task.ID, 2*perRepo)
+			break
+		}
+	}
+}
+
+// benchmarkSizes are the task counts the repository benchmarks run at
+var benchmarkSizes = []int{10_000, 100_000}
+
+// writeBenchmarkTasks writes n tasks with mixed statuses to a temporary file
+func writeBenchmarkTasks(b *testing.B, n int, format Format) string {
+	b.Helper()
+
+	statuses := []entity.TaskStatus{entity.TaskStatusToDo, entity.TaskStatusInProgress, entity.TaskStatusDone}
+	tasks := make([]*entity.Task, n)
+	for i := range tasks {
+		task := entity.NewTask(i+1, fmt.Sprintf("Task %d", i+1), "Benchmark task description")
+		task.Status = statuses[i%len(statuses)]
+		tasks[i] = task
+	}
+
+	path := filepath.Join(b.TempDir(), "tasks.json")
+	if err := NewFileTaskRepository(path, FileOptions{}).saveTasks(tasks, format, nil); err != nil {
+		b.Fatal(err)
+	}
+
+	// Age the file past racyWindow, as a file written by an earlier run would be
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		b.Fatal(err)
+	}
+	return path
+}
+
+// runRepositoryBenchmark measures op against a fresh repository per iteration,
+// which parses the file every time, and against one warmed-up repository
+func runRepositoryBenchmark(b *testing.B, op func(r *FileTaskRepository, n int) error) {
+	for _, n := range benchmarkSizes {
+		path := writeBenchmarkTasks(b, n, FormatJSON)
+
+		b.Run(fmt.Sprintf("tasks=%d/uncached", n), func(b *testing.B) {
+			for b.Loop() {
+				if err := op(NewFileTaskRepository(path, FileOptions{}), n); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("tasks=%d/cached", n), func(b *testing.B) {
+			r := NewFileTaskRepository(path, FileOptions{})
+			if err := op(r, n); err != nil {
+				b.Fatal(err)
+			}
+			for b.Loop() {
+				if err := op(r, n); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGetByID(b *testing.B) {
+	runRepositoryBenchmark(b, func(r *FileTaskRepository, n int) error {
+		_, err := r.GetByID(n / 2)
+		return err
+	})
+}
+
+func BenchmarkGetByStatus(b *testing.B) {
+	runRepositoryBenchmark(b, func(r *FileTaskRepository, n int) error {
+		_, err := r.GetByStatus(entity.TaskStatusInProgress)
+		return err
+	})
+}
+
+func BenchmarkGetNextID(b *testing.B) {
+	runRepositoryBenchmark(b, func(r *FileTaskRepository, n int) error {
+		_, err := r.GetNextID()
+		return err
+	})
+}
+
+func BenchmarkListPage(b *testing.B) {
+	runRepositoryBenchmark(b, func(r *FileTaskRepository, n int) error {
+		_, err := r.List(ListOptions{
+			Statuses: []entity.TaskStatus{entity.TaskStatusToDo, entity.TaskStatusInProgress},
+			Offset:   n / 3,
+			Limit:    20,
+		})
+		return err
+	})
+}
+
+func BenchmarkLoad(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		for _, format := range []Format{FormatJSON, FormatBinary} {
+			path := writeBenchmarkTasks(b, n, format)
+			info, err := os.Stat(path)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.Run(fmt.Sprintf("tasks=%d/%s", n, format), func(b *testing.B) {
+				for b.Loop() {
+					if _, err := NewFileTaskRepository(path, FileOptions{}).GetNextID(); err != nil {
+						b.Fatal(err)
+					}
+				}
+				b.ReportMetric(float64(info.Size()), "file-bytes")
+			})
+		}
+	}
+}
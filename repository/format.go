@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+)
+
+// Format is an on-disk encoding of the task list
+type Format string
+
+// Supported storage formats
+const (
+	// FormatJSON is indented, human-editable JSON
+	FormatJSON Format = "json"
+	// FormatBinary is a compact encoding/gob stream behind a magic header
+	FormatBinary Format = "binary"
+)
+
+// binaryMagic starts every binary tasks file so the format can be detected
+var binaryMagic = []byte("TTGOB1\n")
+
+// ParseFormat validates a storage format name
+func ParseFormat(name string) (Format, error) {
+	switch Format(name) {
+	case FormatJSON, FormatBinary:
+		return Format(name), nil
+	default:
+		return "", fmt.Errorf("%w: invalid storage format: %s. Valid formats: json, binary", entity.ErrInvalidInput, name)
+	}
+}
+
+// FileOptions configures how a FileTaskRepository stores tasks
+type FileOptions struct {
+	// Format is used when creating a new file; existing files keep their format
+	Format Format
+	// Secret returns the passphrase or key file contents for an encrypted
+	// file; it is only called when one has to be opened
+	Secret func() (Secret, error)
+}
+
+// encodeTasks serializes tasks in the given format
+func encodeTasks(tasks []*entity.Task, format Format) ([]byte, error) {
+	if format != FormatBinary {
+		return json.MarshalIndent(tasks, "", "  ")
+	}
+
+	var buf bytes.Buffer
+	buf.Write(binaryMagic)
+	if err := gob.NewEncoder(&buf).Encode(tasks); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeTasks deserializes tasks, detecting the format from the data
+func decodeTasks(data []byte) ([]*entity.Task, Format, error) {
+	var tasks []*entity.Task
+	if rest, ok := bytes.CutPrefix(data, binaryMagic); ok {
+		if err := gob.NewDecoder(bytes.NewReader(rest)).Decode(&tasks); err != nil {
+			return nil, FormatBinary, err
+		}
+		return versioned(tasks), FormatBinary, nil
+	}
+
+	// Handle empty file
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, FormatJSON, nil
+	}
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return nil, FormatJSON, err
+	}
+	return versioned(tasks), FormatJSON, nil
+}
+
+// versioned gives tasks written before versions were tracked version 1, so
+// that a client holding no version cannot match them by sending 0
+func versioned(tasks []*entity.Task) []*entity.Task {
+	for _, task := range tasks {
+		if task.Version < 1 {
+			task.Version = 1
+		}
+	}
+	return tasks
+}
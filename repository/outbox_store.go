@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+)
+
+// OutboxStore persists the webhook deliveries that have not succeeded yet
+type OutboxStore interface {
+	// Load returns the stored outbox, or an empty one if there is none
+	Load() (*entity.Outbox, error)
+	// Update loads the outbox, lets fn change it and saves it, keeping other
+	// processes from changing it meanwhile; nothing is saved when fn fails
+	Update(fn func(outbox *entity.Outbox) error) error
+}
+
+// FileOutboxStore implements OutboxStore using a JSON file that only its owner
+// can read. The payloads hold whole tasks, so when the tasks file is encrypted
+// the outbox is sealed with the same key
+type FileOutboxStore struct {
+	filePath string
+	tasks    *FileTaskRepository
+}
+
+// NewFileOutboxStore creates a new file outbox store for the outbox of tasks
+func NewFileOutboxStore(filePath string, tasks *FileTaskRepository) *FileOutboxStore {
+	return &FileOutboxStore{filePath: filePath, tasks: tasks}
+}
+
+// Load reads the outbox from the file
+func (s *FileOutboxStore) Load() (*entity.Outbox, error) {
+	unlock, err := lockFile(s.filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return s.load()
+}
+
+// Update changes the outbox under a lock on the file
+func (s *FileOutboxStore) Update(fn func(outbox *entity.Outbox) error) error {
+	unlock, err := lockFile(s.filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	outbox, err := s.load()
+	if err != nil {
+		return err
+	}
+	if err := fn(outbox); err != nil {
+		return err
+	}
+	return s.save(outbox)
+}
+
+// load reads and, if it is sealed, decrypts the outbox file
+func (s *FileOutboxStore) load() (*entity.Outbox, error) {
+	outbox := &entity.Outbox{}
+	data, err := os.ReadFile(s.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return outbox, nil
+	}
+	if err != nil {
+		return nil, &entity.StorageError{Op: "read " + s.filePath, Err: err}
+	}
+
+	if isEncrypted(data) {
+		cache, err := s.tasks.snapshot()
+		if err != nil {
+			return nil, err
+		}
+		if data, _, err = openSealed(data, cache.sealing, s.tasks.options.Secret); err != nil {
+			return nil, &entity.StorageError{Op: "decrypt " + s.filePath, Err: err}
+		}
+	}
+	if err := json.Unmarshal(data, outbox); err != nil {
+		return nil, &entity.StorageError{Op: "unmarshal " + s.filePath, Err: err}
+	}
+	return outbox, nil
+}
+
+// save writes the outbox sealed like the tasks file, removing the file once
+// the outbox is empty
+func (s *FileOutboxStore) save(outbox *entity.Outbox) error {
+	if len(outbox.Entries) == 0 {
+		if err := os.Remove(s.filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return &entity.StorageError{Op: "remove " + s.filePath, Err: err}
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(outbox, "", "  ")
+	if err != nil {
+		return &entity.StorageError{Op: "marshal " + s.filePath, Err: err}
+	}
+	cache, err := s.tasks.snapshot()
+	if err != nil {
+		return err
+	}
+	if cache.sealing != nil {
+		if data, err = cache.sealing.seal(data); err != nil {
+			return &entity.StorageError{Op: "encrypt " + s.filePath, Err: err}
+		}
+	}
+	if err := writeFileAtomic(s.filePath, data, 0600); err != nil {
+		return &entity.StorageError{Op: "write " + s.filePath, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+)
+
+func TestOutboxSealedWithTasksKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	tasks := NewFileTaskRepository(path, FileOptions{})
+	if err := tasks.Create(entity.NewTask(1, "Quarterly numbers", "")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tasks.Encrypt(Secret{Kind: SecretKeyFile, Value: []byte("0123456789abcdef0123456789abcdef")}); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewFileOutboxStore(path+".outbox", tasks)
+	err := store.Update(func(outbox *entity.Outbox) error {
+		outbox.Entries = append(outbox.Entries, &entity.OutboxEntry{ID: "1", Payload: []byte(`{"title":"Quarterly numbers"}`)})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path + ".outbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isEncrypted(data) || bytes.Contains(data, []byte("Quarterly")) {
+		t.Error("outbox of an encrypted tasks file is stored in plaintext")
+	}
+
+	outbox, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outbox.Entries) != 1 {
+		t.Fatalf("loaded %d entries, want 1", len(outbox.Entries))
+	}
+	var payload bytes.Buffer
+	if err := json.Compact(&payload, outbox.Entries[0].Payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.String() != `{"title":"Quarterly numbers"}` {
+		t.Errorf("loaded payload = %s, want the stored one", payload.String())
+	}
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+)
+
+// LoadReminderState reads the record of delivered reminders; a missing file means none were sent
+func LoadReminderState(filePath string) (*entity.ReminderState, error) {
+	state := &entity.ReminderState{}
+	if err := loadJSONFile(filePath, state); err != nil {
+		return nil, err
+	}
+	if state.Sent == nil {
+		state.Sent = make(map[string]time.Time)
+	}
+	return state, nil
+}
+
+// SaveReminderState writes the record of delivered reminders
+func SaveReminderState(filePath string, state *entity.ReminderState) error {
+	return saveJSONFile(filePath, state)
+}
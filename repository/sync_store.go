@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+)
+
+// SyncStore persists the state of a sync server
+type SyncStore interface {
+	// Load returns the stored state, or an empty state if there is none
+	Load() (*entity.SyncServerState, error)
+	// Save replaces the stored state
+	Save(state *entity.SyncServerState) error
+}
+
+// FileSyncStore implements SyncStore using a JSON file
+type FileSyncStore struct {
+	filePath string
+}
+
+// NewFileSyncStore creates a new file sync store
+func NewFileSyncStore(filePath string) *FileSyncStore {
+	return &FileSyncStore{filePath: filePath}
+}
+
+// Load reads the server state from the JSON file
+func (s *FileSyncStore) Load() (*entity.SyncServerState, error) {
+	state := &entity.SyncServerState{}
+	if err := loadJSONFile(s.filePath, state); err != nil {
+		return nil, err
+	}
+	if state.Records == nil {
+		state.Records = make(map[int]*entity.SyncRecord)
+	}
+	return state, nil
+}
+
+// Save writes the server state to the JSON file
+func (s *FileSyncStore) Save(state *entity.SyncServerState) error {
+	return saveJSONFile(s.filePath, state)
+}
+
+// LoadSyncState reads a device's sync state; a missing file means the device has never synced
+func LoadSyncState(filePath string) (*entity.SyncState, error) {
+	state := &entity.SyncState{}
+	if err := loadJSONFile(filePath, state); err != nil {
+		return nil, err
+	}
+	if state.Fields == nil {
+		state.Fields = make(map[int]map[string]string)
+	}
+	return state, nil
+}
+
+// SaveSyncState writes a device's sync state
+func SaveSyncState(filePath string, state *entity.SyncState) error {
+	return saveJSONFile(filePath, state)
+}
+
+// loadJSONFile decodes the JSON file at path into v, leaving v untouched if the file does not exist
+func loadJSONFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return &entity.StorageError{Op: "read " + path, Err: err}
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return &entity.StorageError{Op: "unmarshal " + path, Err: err}
+	}
+	return nil
+}
+
+// saveJSONFile encodes v as indented JSON to path, readable only by its owner
+func saveJSONFile(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return &entity.StorageError{Op: "marshal " + path, Err: err}
+	}
+	if err := writeFileAtomic(path, data, 0600); err != nil {
+		return &entity.StorageError{Op: "write " + path, Err: err}
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"iter"
+	"slices"
+
 	"github.com/Illuminateee/task-tracker.git/entity"
 )
 
@@ -18,7 +21,15 @@ type TaskRepository interface {
 	// GetByStatus retrieves tasks filtered by status
 	GetByStatus(status entity.TaskStatus) ([]*entity.Task, error)
 
-	// Update modifies an existing task
+	// All iterates over tasks in ID order; a read failure is yielded as the
+	// only error
+	All() iter.Seq2[*entity.Task, error]
+
+	// List returns one page of the tasks matching opts
+	List(opts ListOptions) (*TaskPage, error)
+
+	// Update modifies an existing task, returning a conflict error when the
+	// stored version differs from task.Version and incrementing it otherwise
 	Update(task *entity.Task) error
 
 	// Delete removes a task by ID
@@ -26,4 +37,60 @@ type TaskRepository interface {
 
 	// GetNextID returns the next available ID
 	GetNextID() (int, error)
+
+	// WithTx runs fn as a single unit of work: the store is read once and
+	// all changes made through tx are saved together when fn returns nil,
+	// or discarded when it returns an error
+	WithTx(fn func(tx TaskTx) error) error
+}
+
+// TaskTx defines the operations available inside a transaction; tasks it
+// returns are copies, so changes must be written back with Update
+type TaskTx interface {
+	// GetByID retrieves a task by its ID
+	GetByID(id int) (*entity.Task, error)
+
+	// GetAll retrieves all tasks sorted by ID
+	GetAll() ([]*entity.Task, error)
+
+	// Create adds a new task
+	Create(task *entity.Task) error
+
+	// Update modifies an existing task, checking and incrementing its version
+	Update(task *entity.Task) error
+
+	// Delete removes a task by ID
+	Delete(id int) error
+
+	// NextID returns the next available ID, including tasks created in the transaction
+	NextID() (int, error)
+}
+
+// ListOptions selects a page of tasks
+type ListOptions struct {
+	// Statuses restricts the page to these statuses; empty means all
+	Statuses []entity.TaskStatus
+	// AfterID skips tasks with an ID less than or equal to it, for cursor paging
+	AfterID int
+	// Offset skips this many matching tasks
+	Offset int
+	// Limit caps the number of tasks returned; zero means no limit
+	Limit int
+}
+
+// Matches reports whether task satisfies the status and cursor filters
+func (o ListOptions) Matches(task *entity.Task) bool {
+	if task.ID <= o.AfterID {
+		return false
+	}
+	return len(o.Statuses) == 0 || slices.Contains(o.Statuses, task.Status)
+}
+
+// TaskPage is one page of a task listing
+type TaskPage struct {
+	Tasks []*entity.Task
+	// Total is the number of tasks matching the filters across all pages
+	Total  int
+	Offset int
+	Limit  int
 }
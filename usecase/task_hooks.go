@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+	"github.com/Illuminateee/task-tracker.git/repository"
+)
+
+// maxChangeAttempts bounds how often a change is prepared again after another
+// write got to its tasks first
+const maxChangeAttempts = 3
+
+// TaskHook is consulted before every change is stored. It vetoes the change
+// by returning an error, or rewrites the task to be stored by returning it;
+// returning nil keeps the change as it is
+type TaskHook interface {
+	BeforeChange(event entity.TaskEvent) (*entity.Task, error)
+}
+
+// TaskListener is told about every change once it is stored; listeners
+// cannot undo a change, so they handle their own failures
+type TaskListener interface {
+	TaskChanged(event entity.TaskEvent)
+}
+
+// TaskJournal records changes durably before they are stored, so that none
+// is lost if the process stops right after storing them. done is called with
+// whether the changes were stored
+type TaskJournal interface {
+	Record(events []entity.TaskEvent) (done func(stored bool), err error)
+}
+
+// AddHook registers a hook consulted before every change made through the use case
+func (uc *TaskUseCase) AddHook(hook TaskHook) {
+	uc.hooks = append(uc.hooks, hook)
+}
+
+// AddListener registers a listener told about every change made through the use case
+func (uc *TaskUseCase) AddListener(listener TaskListener) {
+	uc.listeners = append(uc.listeners, listener)
+}
+
+// AddJournal registers a journal recording every change made through the use case
+func (uc *TaskUseCase) AddJournal(journal TaskJournal) {
+	uc.journals = append(uc.journals, journal)
+}
+
+// change makes one write without holding the repository lock while the
+// hooks run: prepare reads the tasks and describes the change as events, the
+// hooks pass them, the journals record them, and they are stored only if the
+// tasks they were made from are still the stored ones. When another write got
+// there first, the change is prepared again if retry is set and fails with a
+// conflict otherwise. The stored events are returned after the listeners are
+// told
+func (uc *TaskUseCase) change(retry bool, prepare func() ([]entity.TaskEvent, error)) ([]entity.TaskEvent, error) {
+	for attempt := 1; ; attempt++ {
+		events, err := prepare()
+		if err != nil {
+			return nil, err
+		}
+		for i := range events {
+			if events[i], err = uc.runHooks(events[i]); err != nil {
+				return nil, err
+			}
+			// Journals and listeners see the version the task is stored at
+			if before, after := events[i].Before, events[i].After; before != nil && after != nil {
+				after.Version = before.Version + 1
+			}
+		}
+
+		dones, err := uc.record(events)
+		if err != nil {
+			return nil, err
+		}
+		err = uc.taskRepo.WithTx(func(tx repository.TaskTx) error {
+			for _, event := range events {
+				if err := storeEvent(tx, event); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		for _, done := range dones {
+			done(err == nil)
+		}
+		if err == nil {
+			uc.notify(events)
+			return events, nil
+		}
+		raced := errors.Is(err, entity.ErrConflict) || errors.Is(err, entity.ErrTaskNotFound)
+		if !retry || !raced || attempt == maxChangeAttempts {
+			return nil, err
+		}
+	}
+}
+
+// record passes events to the journals, taking them back from the journals
+// that recorded them if one fails
+func (uc *TaskUseCase) record(events []entity.TaskEvent) ([]func(stored bool), error) {
+	dones := make([]func(stored bool), 0, len(uc.journals))
+	for _, journal := range uc.journals {
+		done, err := journal.Record(events)
+		if err != nil {
+			for _, done := range dones {
+				done(false)
+			}
+			return nil, err
+		}
+		dones = append(dones, done)
+	}
+	return dones, nil
+}
+
+// storeEvent writes one change, failing with a conflict when the task it was
+// made from has changed since it was read
+func storeEvent(tx repository.TaskTx, event entity.TaskEvent) error {
+	switch {
+	case event.Before == nil:
+		return tx.Create(event.After)
+	case event.After == nil:
+		current, err := tx.GetByID(event.Before.ID)
+		if err != nil {
+			return err
+		}
+		if current.Version != event.Before.Version {
+			return entity.NewVersionConflictError(current.ID, current.Version, event.Before.Version)
+		}
+		return tx.Delete(event.Before.ID)
+	default:
+		// Update checks the version the change was made from and moves it on
+		task := event.After.Clone()
+		task.Version = event.Before.Version
+		return tx.Update(task)
+	}
+}
+
+// runHooks passes a change through the hooks in order and returns it as it
+// will be stored: event.After unless a hook rewrote it, with its status
+// change recorded
+func (uc *TaskUseCase) runHooks(event entity.TaskEvent) (entity.TaskEvent, error) {
+	for _, hook := range uc.hooks {
+		rewritten, err := hook.BeforeChange(event)
+		if err != nil {
+			return event, err
+		}
+		if rewritten == nil || event.After == nil {
+			continue
+		}
+
+		after, err := applyRewrite(event.After, rewritten)
+		if err != nil {
+			return event, err
+		}
+		event.After = after
+	}
+	// Recorded after the hooks so a status they rewrote is the one in the
+	// history; a task added with a history, as pulled by sync, continues it
+	if after := event.After; after != nil {
+		previous := event.Before
+		if previous == nil && len(after.History) > 0 {
+			previous = &entity.Task{Status: after.History[len(after.History)-1].Status}
+		}
+		after.RecordStatusChange(previous)
+	}
+	return event, nil
+}
+
+// notify tells the listeners about changes that have been stored
+func (uc *TaskUseCase) notify(events []entity.TaskEvent) {
+	for _, event := range events {
+		for _, listener := range uc.listeners {
+			listener.TaskChanged(event)
+		}
+	}
+}
+
+// applyRewrite takes the user-editable fields of a task rewritten by a hook;
+// the ID, timestamps and version stay under the tracker's control
+func applyRewrite(task, rewritten *entity.Task) (*entity.Task, error) {
+	if rewritten.Title == "" {
+		return nil, fmt.Errorf("%w: hook returned a task without a title", entity.ErrInvalidInput)
+	}
+	if !entity.IsValidStatus(string(rewritten.Status)) {
+		return nil, fmt.Errorf("%w: hook returned invalid status %q", entity.ErrInvalidInput, rewritten.Status)
+	}
+
+	result := task.Clone()
+	for _, field := range mergeFields {
+		field.set(result, field.get(rewritten))
+	}
+	return result, nil
+}
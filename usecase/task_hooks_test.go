@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+	"github.com/Illuminateee/task-tracker.git/repository"
+)
+
+// hookFunc adapts a function to TaskHook
+type hookFunc func(event entity.TaskEvent) (*entity.Task, error)
+
+func (f hookFunc) BeforeChange(event entity.TaskEvent) (*entity.Task, error) {
+	return f(event)
+}
+
+// newUseCasePair returns two use cases over the same tasks file, as two
+// processes would have, with one task in it
+func newUseCasePair(t *testing.T) (*TaskUseCase, *TaskUseCase) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	first := NewTaskUseCase(repository.NewFileTaskRepository(path, repository.FileOptions{}))
+	second := NewTaskUseCase(repository.NewFileTaskRepository(path, repository.FileOptions{}))
+	if _, err := first.CreateTask("Write report", ""); err != nil {
+		t.Fatal(err)
+	}
+	return first, second
+}
+
+func TestHooksRunWithoutHoldingTheLock(t *testing.T) {
+	uc, other := newUseCasePair(t)
+
+	// A hook that writes to the store, as a script running task-tracker
+	// would, must not wait for the change it is judging
+	uc.AddHook(hookFunc(func(event entity.TaskEvent) (*entity.Task, error) {
+		if event.Type != entity.TaskAdded {
+			return nil, nil
+		}
+		done := make(chan error, 1)
+		go func() {
+			_, err := other.SetTaskDue(1, nil)
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			return nil, err
+		case <-time.After(5 * time.Second):
+			return nil, errors.New("store locked while the hook ran")
+		}
+	}))
+
+	if _, err := uc.CreateTask("Review report", ""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChangeRacedWhileHooksRan(t *testing.T) {
+	uc, other := newUseCasePair(t)
+
+	// race, when set, runs once while the hooks judge the next change
+	var race func() error
+	var runs int
+	uc.AddHook(hookFunc(func(event entity.TaskEvent) (*entity.Task, error) {
+		runs++
+		if race == nil {
+			return nil, nil
+		}
+		err := race()
+		race = nil
+		return nil, err
+	}))
+
+	// A change at a given version fails once another write got there first
+	race = func() error {
+		_, err := other.UpdateTaskStatus(1, entity.TaskStatusInProgress)
+		return err
+	}
+	if _, err := uc.UpdateTaskAtVersion(1, 1, "Write the report", ""); !errors.Is(err, entity.ErrConflict) {
+		t.Fatalf("update at a raced version = %v, want a conflict", err)
+	}
+
+	// Without a version the change is prepared again from the stored task
+	runs = 0
+	race = func() error {
+		_, err := other.UpdateTask(1, "", "Raced")
+		return err
+	}
+	task, err := uc.UpdateTask(1, "Write the report", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runs != 2 {
+		t.Errorf("hooks ran %d times, want 2", runs)
+	}
+	if task.Title != "Write the report" || task.Description != "Raced" || task.Status != entity.TaskStatusInProgress {
+		t.Errorf("task = %q / %q / %s, want both writes kept", task.Title, task.Description, task.Status)
+	}
+}
@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+)
+
+// MergeConflict is a change made on both sides that could not be reconciled;
+// the merged task keeps the value from ours, or the modified task when the
+// other side deleted it
+type MergeConflict struct {
+	TaskID int
+	// Field is the conflicting field, or "task" when one side deleted the task
+	Field  string
+	Base   string
+	Ours   string
+	Theirs string
+}
+
+// MergeResult is the outcome of a three-way merge
+type MergeResult struct {
+	Tasks     []*entity.Task
+	Conflicts []MergeConflict
+	// Renumbered maps the IDs of tasks added in theirs to the IDs they were
+	// given because ours added a different task with the same ID
+	Renumbered map[int]int
+}
+
+// mergeField is a task field merged, and synced, independently of the others
+type mergeField struct {
+	name string
+	get  func(t *entity.Task) string
+	set  func(t *entity.Task, value string)
+}
+
+// mergeFields lists the user-editable fields merged field by field
+var mergeFields = []mergeField{
+	{
+		name: "title",
+		get:  func(t *entity.Task) string { return t.Title },
+		set:  func(t *entity.Task, value string) { t.Title = value },
+	},
+	{
+		name: "description",
+		get:  func(t *entity.Task) string { return t.Description },
+		set:  func(t *entity.Task, value string) { t.Description = value },
+	},
+	{
+		name: "status",
+		get:  func(t *entity.Task) string { return string(t.Status) },
+		set: func(t *entity.Task, value string) {
+			if entity.IsValidStatus(value) {
+				t.Status = entity.TaskStatus(value)
+			}
+		},
+	},
+	{
+		name: "due",
+		get: func(t *entity.Task) string {
+			if t.Due == nil {
+				return ""
+			}
+			return t.Due.UTC().Format(time.RFC3339)
+		},
+		set: func(t *entity.Task, value string) {
+			if value == "" {
+				t.Due = nil
+			} else if due, err := time.Parse(time.RFC3339, value); err == nil {
+				t.Due = &due
+			}
+		},
+	},
+}
+
+// MergeTasks merges two task lists that diverged from base, matching tasks by ID
+func MergeTasks(base, ours, theirs []*entity.Task) *MergeResult {
+	baseByID, oursByID, theirsByID := indexTasks(base), indexTasks(ours), indexTasks(theirs)
+	result := &MergeResult{Renumbered: make(map[int]int)}
+
+	ids := make(map[int]bool)
+	for _, tasks := range [][]*entity.Task{base, ours, theirs} {
+		for _, task := range tasks {
+			ids[task.ID] = true
+		}
+	}
+
+	var collisions []*entity.Task
+	for _, id := range slices.Sorted(maps.Keys(ids)) {
+		b, o, t := baseByID[id], oursByID[id], theirsByID[id]
+		switch {
+		case o != nil && t != nil && b != nil:
+			result.Tasks = append(result.Tasks, mergeTask(b, o, t, result))
+		case o != nil && t != nil:
+			// Added on both sides
+			result.Tasks = append(result.Tasks, o.Clone())
+			if !sameFields(o, t) {
+				collisions = append(collisions, t)
+			}
+		case b == nil:
+			// Added on one side
+			result.Tasks = append(result.Tasks, firstTask(o, t).Clone())
+		case o != nil:
+			// Deleted in theirs
+			if !sameFields(b, o) {
+				result.Tasks = append(result.Tasks, o.Clone())
+				result.addDeleteConflict(b, summarizeTask(o), "(deleted)")
+			}
+		case t != nil:
+			// Deleted in ours
+			if !sameFields(b, t) {
+				result.Tasks = append(result.Tasks, t.Clone())
+				result.addDeleteConflict(b, "(deleted)", summarizeTask(t))
+			}
+		}
+	}
+
+	// Tasks both sides added under the same ID are kept, theirs with a new ID
+	nextID := 0
+	for id := range ids {
+		nextID = max(nextID, id)
+	}
+	for _, task := range collisions {
+		nextID++
+		renumbered := task.Clone()
+		renumbered.ID = nextID
+		result.Renumbered[task.ID] = nextID
+		result.Tasks = append(result.Tasks, renumbered)
+	}
+	return result
+}
+
+// mergeTask merges a task changed on both sides, field by field
+func mergeTask(base, ours, theirs *entity.Task, result *MergeResult) *entity.Task {
+	merged := ours.Clone()
+	for _, field := range mergeFields {
+		b, o, t := field.get(base), field.get(ours), field.get(theirs)
+		switch {
+		case o == t, t == b:
+			// Same change on both sides, or only ours changed
+		case o == b:
+			field.set(merged, t)
+		default:
+			result.Conflicts = append(result.Conflicts, MergeConflict{
+				TaskID: base.ID, Field: field.name, Base: b, Ours: o, Theirs: t,
+			})
+		}
+	}
+
+	if theirs.UpdatedAt.After(merged.UpdatedAt) {
+		merged.UpdatedAt = theirs.UpdatedAt
+	}
+	merged.History = mergeHistory(ours.History, theirs.History)
+	// A task combining both sides is newer than either of them
+	merged.Version = max(ours.Version, theirs.Version)
+	if !sameFields(merged, ours) && !sameFields(merged, theirs) {
+		merged.Version++
+	}
+	return merged
+}
+
+// addDeleteConflict records a task deleted on one side and modified on the
+// other; the modified task is kept
+func (r *MergeResult) addDeleteConflict(base *entity.Task, ours, theirs string) {
+	r.Conflicts = append(r.Conflicts, MergeConflict{
+		TaskID: base.ID,
+		Field:  "task",
+		Base:   summarizeTask(base),
+		Ours:   ours,
+		Theirs: theirs,
+	})
+}
+
+// Report formats the conflicts with git-style conflict markers
+func (r *MergeResult) Report() string {
+	var b strings.Builder
+	for i, conflict := range r.Conflicts {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "Task %d: %s\n", conflict.TaskID, conflict.Field)
+		fmt.Fprintf(&b, "<<<<<<< ours\n%s\n", conflict.Ours)
+		fmt.Fprintf(&b, "||||||| base\n%s\n", conflict.Base)
+		fmt.Fprintf(&b, "=======\n%s\n", conflict.Theirs)
+		b.WriteString(">>>>>>> theirs\n")
+	}
+	return b.String()
+}
+
+// mergeHistory combines the status changes recorded on both sides in time order
+func mergeHistory(ours, theirs []entity.StatusChange) []entity.StatusChange {
+	history := slices.Concat(ours, theirs)
+	slices.SortStableFunc(history, func(a, b entity.StatusChange) int {
+		return a.At.Compare(b.At)
+	})
+	return slices.CompactFunc(history, func(a, b entity.StatusChange) bool {
+		return a.Status == b.Status && a.At.Equal(b.At)
+	})
+}
+
+// sameFields reports whether two tasks have the same merged fields
+func sameFields(a, b *entity.Task) bool {
+	for _, field := range mergeFields {
+		if field.get(a) != field.get(b) {
+			return false
+		}
+	}
+	return true
+}
+
+// summarizeTask formats the merged fields of a task on one line
+func summarizeTask(t *entity.Task) string {
+	parts := make([]string, len(mergeFields))
+	for i, field := range mergeFields {
+		parts[i] = fmt.Sprintf("%s=%q", field.name, field.get(t))
+	}
+	return strings.Join(parts, " ")
+}
+
+// indexTasks maps tasks by ID
+func indexTasks(tasks []*entity.Task) map[int]*entity.Task {
+	byID := make(map[int]*entity.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+	return byID
+}
+
+// firstTask returns the first non-nil task
+func firstTask(tasks ...*entity.Task) *entity.Task {
+	for _, task := range tasks {
+		if task != nil {
+			return task
+		}
+	}
+	return nil
+}
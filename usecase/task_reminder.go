@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+)
+
+// ReminderSink delivers reminders somewhere the user will notice them
+type ReminderSink interface {
+	// Name identifies the sink in the reminder state
+	Name() string
+	Send(reminder entity.Reminder) error
+}
+
+// DueReminders returns a reminder for every unfinished task that is overdue
+// or due within lead of now
+func (uc *TaskUseCase) DueReminders(now time.Time, lead time.Duration) ([]entity.Reminder, error) {
+	var reminders []entity.Reminder
+	for task, err := range uc.taskRepo.All() {
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tasks: %w", err)
+		}
+		if task.Due == nil || task.Status == entity.TaskStatusDone {
+			continue
+		}
+
+		switch {
+		case task.IsOverdue(now):
+			reminders = append(reminders, entity.Reminder{Kind: entity.ReminderOverdue, Task: task})
+		case task.Due.Sub(now) <= lead:
+			reminders = append(reminders, entity.Reminder{Kind: entity.ReminderUpcoming, Task: task})
+		}
+	}
+	return reminders, nil
+}
+
+// SendReminders delivers each due reminder through every sink that has not
+// delivered it yet and records the deliveries in state; a failed delivery is
+// retried on the next call
+func (uc *TaskUseCase) SendReminders(state *entity.ReminderState, sinks []ReminderSink, now time.Time, lead time.Duration) (int, error) {
+	reminders, err := uc.DueReminders(now, lead)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var errs []error
+	current := make(map[string]bool)
+	for _, reminder := range reminders {
+		current[reminderKey(reminder)] = true
+		for _, sink := range sinks {
+			key := reminderKey(reminder) + "/" + sink.Name()
+			if _, ok := state.Sent[key]; ok {
+				continue
+			}
+
+			if err := sink.Send(reminder); err != nil {
+				errs = append(errs, fmt.Errorf("failed to send reminder for task %d to %s: %w", reminder.Task.ID, sink.Name(), err))
+				continue
+			}
+			state.Sent[key] = now
+			sent++
+		}
+	}
+
+	// Forget reminders that no longer apply, e.g. for finished, deleted or
+	// rescheduled tasks, so that the state does not grow without bound
+	for key := range state.Sent {
+		if !current[key[:strings.LastIndex(key, "/")]] {
+			delete(state.Sent, key)
+		}
+	}
+	return sent, errors.Join(errs...)
+}
+
+// reminderKey identifies a reminder; it includes the due date so that
+// rescheduling a task arms its reminders again. Deliveries are recorded under
+// the key followed by a slash and the sink name
+func reminderKey(reminder entity.Reminder) string {
+	return fmt.Sprintf("%d/%s/%s", reminder.Task.ID, reminder.Kind, reminder.Task.Due.UTC().Format(time.RFC3339))
+}
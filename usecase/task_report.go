@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+)
+
+// TaskReport summarizes the work in a time window for standups and weekly updates
+type TaskReport struct {
+	Since time.Time
+	Until time.Time
+	// Completed lists the tasks moved to done within the window, in completion order
+	Completed []*entity.Task
+	// Added lists the tasks created within the window
+	Added []*entity.Task
+	// InProgress lists the tasks currently in progress
+	InProgress []*entity.Task
+	// Overdue lists the unfinished tasks past their due date at the end of the window
+	Overdue []*entity.Task
+}
+
+// Report summarizes the tasks completed and added between since and until,
+// the ones currently in progress and the ones overdue at until
+func (uc *TaskUseCase) Report(since, until time.Time) (*TaskReport, error) {
+	if !since.Before(until) {
+		return nil, fmt.Errorf("%w: report window must start before it ends", entity.ErrInvalidInput)
+	}
+
+	report := &TaskReport{Since: since, Until: until}
+	inWindow := func(t time.Time) bool {
+		return !t.Before(since) && t.Before(until)
+	}
+	for task, err := range uc.taskRepo.All() {
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tasks: %w", err)
+		}
+
+		if inWindow(task.CreatedAt) {
+			report.Added = append(report.Added, task)
+		}
+		if completed, done := task.CompletedAt(); done && inWindow(completed) {
+			report.Completed = append(report.Completed, task)
+		}
+		if task.Status == entity.TaskStatusInProgress {
+			report.InProgress = append(report.InProgress, task)
+		}
+		if task.IsOverdue(until) {
+			report.Overdue = append(report.Overdue, task)
+		}
+	}
+
+	slices.SortStableFunc(report.Completed, func(a, b *entity.Task) int {
+		completedA, _ := a.CompletedAt()
+		completedB, _ := b.CompletedAt()
+		return completedA.Compare(completedB)
+	})
+	slices.SortStableFunc(report.Overdue, func(a, b *entity.Task) int {
+		return cmp.Or(a.Due.Compare(*b.Due), cmp.Compare(a.ID, b.ID))
+	})
+	return report, nil
+}
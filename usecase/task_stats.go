@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+)
+
+// TaskStats summarizes the tasks and how they flow from created to done
+type TaskStats struct {
+	Total    int
+	ByStatus map[entity.TaskStatus]int
+	// Weeks counts the tasks created and completed in each week, oldest first
+	Weeks []WeekStats
+	// CycleTime measures in-progress to done for the tasks completed in Weeks
+	CycleTime DurationStats
+	// LeadTime measures created to done for the tasks completed in Weeks
+	LeadTime DurationStats
+	// Oldest lists the open tasks created longest ago
+	Oldest []*entity.Task
+}
+
+// WeekStats counts the tasks created and completed in the week starting on Start
+type WeekStats struct {
+	Start     time.Time
+	Created   int
+	Completed int
+}
+
+// DurationStats summarizes a set of durations; the other fields are zero when Count is
+type DurationStats struct {
+	Count   int
+	Average time.Duration
+	Median  time.Duration
+	P85     time.Duration
+	P95     time.Duration
+}
+
+// Stats computes the task statistics for the weeks weeks up to and including
+// the one containing now, listing up to oldest open tasks. Weeks start on
+// Monday in now's location
+func (uc *TaskUseCase) Stats(now time.Time, weeks, oldest int) (*TaskStats, error) {
+	if weeks < 1 || oldest < 0 {
+		return nil, fmt.Errorf("%w: weeks must be positive and oldest not negative", entity.ErrInvalidInput)
+	}
+
+	stats := &TaskStats{ByStatus: make(map[entity.TaskStatus]int)}
+	start := StartOfWeek(now).AddDate(0, 0, -7*(weeks-1))
+	for i := range weeks {
+		stats.Weeks = append(stats.Weeks, WeekStats{Start: start.AddDate(0, 0, 7*i)})
+	}
+	end := start.AddDate(0, 0, 7*weeks)
+	// weekIndex returns the week t falls in, or -1 outside the window
+	weekIndex := func(t time.Time) int {
+		if !t.Before(end) {
+			return -1
+		}
+		for i := len(stats.Weeks) - 1; i >= 0; i-- {
+			if !t.Before(stats.Weeks[i].Start) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	var cycleTimes, leadTimes []time.Duration
+	var open []*entity.Task
+	for task, err := range uc.taskRepo.All() {
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tasks: %w", err)
+		}
+		stats.Total++
+		stats.ByStatus[task.Status]++
+
+		if i := weekIndex(task.CreatedAt); i >= 0 {
+			stats.Weeks[i].Created++
+		}
+
+		completed, done := task.CompletedAt()
+		if !done {
+			open = append(open, task)
+			continue
+		}
+		i := weekIndex(completed)
+		if i < 0 {
+			continue
+		}
+		stats.Weeks[i].Completed++
+		leadTimes = append(leadTimes, completed.Sub(task.CreatedAt))
+		if started, ok := task.StartedAt(); ok && !started.After(completed) {
+			cycleTimes = append(cycleTimes, completed.Sub(started))
+		}
+	}
+
+	stats.CycleTime = summarizeDurations(cycleTimes)
+	stats.LeadTime = summarizeDurations(leadTimes)
+
+	slices.SortStableFunc(open, func(a, b *entity.Task) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	stats.Oldest = open[:min(oldest, len(open))]
+	return stats, nil
+}
+
+// StartOfWeek returns midnight of the Monday starting the week containing t
+func StartOfWeek(t time.Time) time.Time {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, t.Location())
+}
+
+// summarizeDurations returns the average and nearest-rank percentiles of durations
+func summarizeDurations(durations []time.Duration) DurationStats {
+	if len(durations) == 0 {
+		return DurationStats{}
+	}
+	slices.Sort(durations)
+
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	percentile := func(p int) time.Duration {
+		rank := (p*len(durations) + 99) / 100
+		return durations[max(rank, 1)-1]
+	}
+	return DurationStats{
+		Count:   len(durations),
+		Average: total / time.Duration(len(durations)),
+		Median:  percentile(50),
+		P85:     percentile(85),
+		P95:     percentile(95),
+	}
+}
@@ -0,0 +1,399 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+	"github.com/Illuminateee/task-tracker.git/repository"
+)
+
+// syncDeleted is the pseudo-field recording whether a task is deleted, so
+// that deletions and later edits also resolve by last writer
+const syncDeleted = "deleted"
+
+// SyncChange is a local change pushed to the sync server
+type SyncChange struct {
+	ID int `json:"id"`
+	// New marks a task created since the last sync; the server may store it under another ID
+	New       bool                        `json:"new,omitempty"`
+	CreatedAt time.Time                   `json:"created_at"`
+	Fields    map[string]entity.SyncValue `json:"fields"`
+}
+
+// SyncRequest is sent by a device to push its changes and pull everyone else's
+type SyncRequest struct {
+	// Since is the last server revision the device has pulled
+	Since   int          `json:"since"`
+	Changes []SyncChange `json:"changes"`
+}
+
+// SyncResponse carries the records changed since the device last synced,
+// including every record the request touched
+type SyncResponse struct {
+	Revision int                  `json:"revision"`
+	Records  []*entity.SyncRecord `json:"records"`
+	// IDs maps the IDs of new tasks to the IDs the server stored them under
+	IDs map[int]int `json:"ids"`
+}
+
+// SyncExchange sends a request to a sync server and returns its response
+type SyncExchange func(req SyncRequest) (*SyncResponse, error)
+
+// SyncResult summarizes a sync
+type SyncResult struct {
+	Pushed int
+	Pulled int
+	// Renumbered maps local IDs of new tasks to the different IDs the server gave them
+	Renumbered map[int]int
+}
+
+// Sync pushes the changes made since state was saved, applies the server's
+// changes in one transaction, through the hooks and listeners like any other
+// change, and updates state to match. Local edits made
+// while the exchange was in flight win over the pulled values and are pushed
+// by the next sync
+func (uc *TaskUseCase) Sync(state *entity.SyncState, exchange SyncExchange) (*SyncResult, error) {
+	tasks, err := uc.taskRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all tasks: %w", err)
+	}
+
+	changes := syncChanges(state, tasks, time.Now())
+	response, err := exchange(SyncRequest{Since: state.Revision, Changes: changes})
+	if err != nil {
+		return nil, err
+	}
+
+	pushed := make(map[int]*entity.Task, len(tasks))
+	for _, task := range tasks {
+		pushed[task.ID] = task
+	}
+
+	result := &SyncResult{Pushed: len(changes), Pulled: len(response.Records), Renumbered: make(map[int]int)}
+	for localID, serverID := range response.IDs {
+		if localID != serverID {
+			result.Renumbered[localID] = serverID
+		}
+	}
+
+	// Pulled changes pass the hooks and reach the listeners like local ones
+	var edits *syncEdits
+	_, err = uc.change(true, func() ([]entity.TaskEvent, error) {
+		tasks, err := uc.taskRepo.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		current := make(map[int]*entity.Task, len(tasks))
+		for _, task := range tasks {
+			current[task.ID] = task
+		}
+		edits = findSyncEdits(current, pushed, response)
+		return syncEvents(current, response, edits), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply synced tasks: %w", err)
+	}
+
+	for localID := range result.Renumbered {
+		delete(state.Fields, localID)
+	}
+	for _, record := range response.Records {
+		// A task created meanwhile under a pulled ID is pushed as a new one
+		if record.Fields[syncDeleted].Value == "true" || edits.created[record.ID] {
+			delete(state.Fields, record.ID)
+			continue
+		}
+		// Fields edited meanwhile keep no hash, so the next sync pushes them
+		edit := edits.changed[record.ID]
+		hashes := make(map[string]string, len(mergeFields))
+		for _, field := range mergeFields {
+			if edit == nil || !slices.Contains(edit.fields, field.name) {
+				hashes[field.name] = hashSyncValue(record.Fields[field.name].Value)
+			}
+		}
+		state.Fields[record.ID] = hashes
+	}
+	// A pulled record that was not applied is pulled again next time
+	if len(edits.created) == 0 {
+		state.Revision = response.Revision
+	}
+	return result, nil
+}
+
+// syncChanges compares tasks with the field hashes of the last sync
+func syncChanges(state *entity.SyncState, tasks []*entity.Task, now time.Time) []SyncChange {
+	var changes []SyncChange
+	local := make(map[int]bool, len(tasks))
+	for _, task := range tasks {
+		local[task.ID] = true
+		hashes, synced := state.Fields[task.ID]
+
+		change := SyncChange{ID: task.ID, New: !synced, CreatedAt: task.CreatedAt, Fields: make(map[string]entity.SyncValue)}
+		for _, field := range mergeFields {
+			value := field.get(task)
+			if synced && hashes[field.name] == hashSyncValue(value) {
+				continue
+			}
+			change.Fields[field.name] = entity.SyncValue{Value: value, At: task.UpdatedAt}
+		}
+		if len(change.Fields) == 0 {
+			continue
+		}
+		// An edit newer than a deletion on another device brings the task back
+		change.Fields[syncDeleted] = entity.SyncValue{Value: "false", At: task.UpdatedAt}
+		changes = append(changes, change)
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(state.Fields)) {
+		if !local[id] {
+			changes = append(changes, SyncChange{
+				ID:     id,
+				Fields: map[string]entity.SyncValue{syncDeleted: {Value: "true", At: now}},
+			})
+		}
+	}
+	return changes
+}
+
+// syncEdit is a local edit made to a task while its sync exchange was in flight
+type syncEdit struct {
+	// task is the task as it is now
+	task *entity.Task
+	// fields names the merged fields changed since the task was pushed
+	fields []string
+}
+
+// syncEdits holds the local changes to pulled records made while a sync
+// exchange was in flight, by record ID
+type syncEdits struct {
+	changed map[int]*syncEdit
+	// deleted holds records whose task was deleted locally; the deletion is
+	// pushed by the next sync
+	deleted map[int]bool
+	// created holds records whose ID a new local task took
+	created map[int]bool
+}
+
+// findSyncEdits compares the current tasks with the pushed tasks they were
+// read as, following new tasks the server gave another ID
+func findSyncEdits(current, pushed map[int]*entity.Task, response *SyncResponse) *syncEdits {
+	edits := &syncEdits{changed: make(map[int]*syncEdit), deleted: make(map[int]bool), created: make(map[int]bool)}
+
+	renumbered := make(map[int]int, len(response.IDs))
+	for localID, serverID := range response.IDs {
+		if localID != serverID {
+			renumbered[serverID] = localID
+		}
+	}
+
+	for _, record := range response.Records {
+		localID, moved := renumbered[record.ID]
+		if !moved {
+			// The local task under this ID moves to the ID the server gave it
+			if serverID, ok := response.IDs[record.ID]; ok && serverID != record.ID {
+				continue
+			}
+			localID = record.ID
+		}
+
+		before, task := pushed[localID], current[localID]
+		switch {
+		case before == nil && task == nil:
+		case before == nil:
+			edits.created[record.ID] = true
+		case task == nil:
+			edits.deleted[record.ID] = true
+		default:
+			edit := &syncEdit{task: task}
+			for _, field := range mergeFields {
+				if field.get(task) != field.get(before) {
+					edit.fields = append(edit.fields, field.name)
+				}
+			}
+			if len(edit.fields) > 0 {
+				edits.changed[record.ID] = edit
+			}
+		}
+	}
+	return edits
+}
+
+// syncEvents describes the changes that make the current tasks match the
+// server's response, leaving alone the records edits keep local
+func syncEvents(current map[int]*entity.Task, response *SyncResponse, edits *syncEdits) []entity.TaskEvent {
+	var events []entity.TaskEvent
+
+	// New tasks given another ID come back under it with the records
+	for _, localID := range slices.Sorted(maps.Keys(response.IDs)) {
+		if task := current[localID]; task != nil && response.IDs[localID] != localID {
+			events = append(events, entity.TaskEvent{Type: entity.TaskDeleted, Before: task})
+			delete(current, localID)
+		}
+	}
+
+	for _, record := range response.Records {
+		if edits.deleted[record.ID] || edits.created[record.ID] {
+			continue
+		}
+		event := syncRecordEvent(current[record.ID], record, edits.changed[record.ID])
+		if event == nil {
+			continue
+		}
+		events = append(events, *event)
+		if event.After == nil {
+			delete(current, record.ID)
+		} else {
+			current[record.ID] = event.After
+		}
+	}
+	return events
+}
+
+// syncRecordEvent describes the change that makes existing, which is nil for
+// a task not stored locally, match a record from the server, keeping the
+// fields of edit, if any; it returns nil when nothing changes
+func syncRecordEvent(existing *entity.Task, record *entity.SyncRecord, edit *syncEdit) *entity.TaskEvent {
+	if record.Fields[syncDeleted].Value == "true" {
+		// A task edited meanwhile stays, and is pushed again as a new one
+		if existing == nil || edit != nil {
+			return nil
+		}
+		return &entity.TaskEvent{Type: entity.TaskDeleted, Before: existing}
+	}
+
+	var task *entity.Task
+	if existing == nil {
+		// The history is kept per device; a pulled status change counts from when it was made
+		task = &entity.Task{ID: record.ID, Status: entity.TaskStatusToDo, CreatedAt: record.CreatedAt, Version: 1}
+		task.History = []entity.StatusChange{{Status: entity.TaskStatusToDo, At: task.CreatedAt}}
+	} else {
+		task = existing.Clone()
+	}
+	for _, field := range mergeFields {
+		if value, ok := record.Fields[field.name]; ok {
+			field.set(task, value.Value)
+			if value.At.After(task.UpdatedAt) {
+				task.UpdatedAt = value.At
+			}
+		}
+	}
+	if edit != nil {
+		for _, field := range mergeFields {
+			if slices.Contains(edit.fields, field.name) {
+				field.set(task, field.get(edit.task))
+			}
+		}
+		if edit.task.UpdatedAt.After(task.UpdatedAt) {
+			task.UpdatedAt = edit.task.UpdatedAt
+		}
+	}
+
+	switch {
+	case existing == nil:
+		return &entity.TaskEvent{Type: entity.TaskAdded, After: task}
+	case sameFields(existing, task):
+		return nil
+	case existing.Status != task.Status:
+		return &entity.TaskEvent{Type: entity.TaskStatusChanged, Before: existing, After: task}
+	default:
+		return &entity.TaskEvent{Type: entity.TaskModified, Before: existing, After: task}
+	}
+}
+
+// hashSyncValue returns the hash a synced field value is remembered by
+func hashSyncValue(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:16])
+}
+
+// SyncServer merges the changes pushed by devices with last-writer-wins per field
+type SyncServer struct {
+	store repository.SyncStore
+	// mu serializes exchanges because the store is read and written whole
+	mu sync.Mutex
+}
+
+// NewSyncServer creates a new sync server
+func NewSyncServer(store repository.SyncStore) *SyncServer {
+	return &SyncServer{store: store}
+}
+
+// Exchange applies a device's changes and returns the records it has not seen
+func (s *SyncServer) Exchange(req SyncRequest) (*SyncResponse, error) {
+	for _, change := range req.Changes {
+		if change.ID <= 0 {
+			return nil, fmt.Errorf("%w: invalid task ID %d", entity.ErrInvalidInput, change.ID)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	maxID := 0
+	for id := range state.Records {
+		maxID = max(maxID, id)
+	}
+
+	response := &SyncResponse{IDs: make(map[int]int)}
+	touched := make(map[int]bool)
+	dirty := false
+	for _, change := range req.Changes {
+		id := change.ID
+		record := state.Records[id]
+		// A new task matches a stored one only if it is the same task, e.g.
+		// from a copied tasks file; otherwise it gets the next free ID
+		if change.New && record != nil && !record.CreatedAt.Equal(change.CreatedAt) {
+			maxID++
+			id, record = maxID, nil
+		}
+		if change.New {
+			response.IDs[change.ID] = id
+		}
+
+		changed := false
+		if record == nil {
+			record = &entity.SyncRecord{ID: id, CreatedAt: change.CreatedAt, Fields: make(map[string]entity.SyncValue)}
+			state.Records[id] = record
+			maxID = max(maxID, id)
+			changed = true
+		}
+		for name, value := range change.Fields {
+			if current, ok := record.Fields[name]; !ok || value.Newer(current) {
+				record.Fields[name] = value
+				changed = true
+			}
+		}
+
+		if changed {
+			state.Revision++
+			record.Rev = state.Revision
+			dirty = true
+		}
+		touched[id] = true
+	}
+
+	if dirty {
+		if err := s.store.Save(state); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(state.Records)) {
+		if record := state.Records[id]; record.Rev > req.Since || touched[id] {
+			response.Records = append(response.Records, record)
+		}
+	}
+	response.Revision = state.Revision
+	return response, nil
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+	"github.com/Illuminateee/task-tracker.git/repository"
+)
+
+func TestSyncKeepsLocalEditMadeDuringExchange(t *testing.T) {
+	dir := t.TempDir()
+	uc := NewTaskUseCase(repository.NewFileTaskRepository(filepath.Join(dir, "tasks.json"), repository.FileOptions{}))
+	server := NewSyncServer(repository.NewFileSyncStore(filepath.Join(dir, "sync-store.json")))
+	state, err := repository.LoadSyncState(filepath.Join(dir, "tasks.json.sync"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.CreateTask("Write report", "Draft"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.Sync(state, server.Exchange); err != nil {
+		t.Fatal(err)
+	}
+
+	// Push a description change, and edit the title while the server answers
+	if _, err := uc.UpdateTask(1, "Write report", "Final"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = uc.Sync(state, func(req SyncRequest) (*SyncResponse, error) {
+		response, err := server.Exchange(req)
+		if err == nil {
+			_, err = uc.UpdateTask(1, "Write the report", "Final")
+		}
+		return response, err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task, err := uc.GetTask(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Title != "Write the report" || task.Description != "Final" {
+		t.Fatalf("task after sync = %q / %q, want the edit made during the exchange kept", task.Title, task.Description)
+	}
+	if _, ok := state.Fields[1]["title"]; ok {
+		t.Error("sync state records the title the exchange overwrote")
+	}
+
+	// The next sync pushes the kept edit
+	if _, err := uc.Sync(state, server.Exchange); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := repository.NewFileSyncStore(filepath.Join(dir, "sync-store.json")).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title := stored.Records[1].Fields["title"].Value; title != "Write the report" {
+		t.Errorf("server title = %q, want the kept edit", title)
+	}
+}
+
+func TestSyncDoesNotRecreateTaskDeletedDuringExchange(t *testing.T) {
+	dir := t.TempDir()
+	uc := NewTaskUseCase(repository.NewFileTaskRepository(filepath.Join(dir, "tasks.json"), repository.FileOptions{}))
+	server := NewSyncServer(repository.NewFileSyncStore(filepath.Join(dir, "sync-store.json")))
+	state := &entity.SyncState{Fields: make(map[int]map[string]string)}
+
+	if _, err := uc.CreateTask("Short-lived", ""); err != nil {
+		t.Fatal(err)
+	}
+	_, err := uc.Sync(state, func(req SyncRequest) (*SyncResponse, error) {
+		response, err := server.Exchange(req)
+		if err == nil {
+			err = uc.DeleteTask(1)
+		}
+		return response, err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.GetTask(1); err == nil {
+		t.Fatal("task deleted during the exchange was recreated")
+	}
+
+	// The next sync pushes the deletion
+	if _, err := uc.Sync(state, server.Exchange); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := repository.NewFileSyncStore(filepath.Join(dir, "sync-store.json")).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted := stored.Records[1].Fields[syncDeleted].Value; deleted != "true" {
+		t.Errorf("server deleted = %q, want true", deleted)
+	}
+}
+
+func TestSyncRunsHooksOnPulledChanges(t *testing.T) {
+	dir := t.TempDir()
+	server := NewSyncServer(repository.NewFileSyncStore(filepath.Join(dir, "sync-store.json")))
+
+	laptop := NewTaskUseCase(repository.NewFileTaskRepository(filepath.Join(dir, "laptop.json"), repository.FileOptions{}))
+	if _, err := laptop.CreateTask("Pulled", ""); err != nil {
+		t.Fatal(err)
+	}
+	laptopState := &entity.SyncState{Fields: make(map[int]map[string]string)}
+	if _, err := laptop.Sync(laptopState, server.Exchange); err != nil {
+		t.Fatal(err)
+	}
+
+	desktop := NewTaskUseCase(repository.NewFileTaskRepository(filepath.Join(dir, "desktop.json"), repository.FileOptions{}))
+	var seen []entity.TaskEvent
+	desktop.AddHook(hookFunc(func(event entity.TaskEvent) (*entity.Task, error) {
+		seen = append(seen, event)
+		return nil, nil
+	}))
+	state := &entity.SyncState{Fields: make(map[int]map[string]string)}
+	if _, err := desktop.Sync(state, server.Exchange); err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 1 || seen[0].Type != entity.TaskAdded || seen[0].After.Title != "Pulled" {
+		t.Fatalf("hooks saw %+v, want the pulled task added", seen)
+	}
+
+	// A vetoing hook keeps the pulled change out
+	desktop.AddHook(hookFunc(func(event entity.TaskEvent) (*entity.Task, error) {
+		return nil, errors.New("vetoed")
+	}))
+	if _, err := laptop.UpdateTaskStatus(1, entity.TaskStatusDone); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := laptop.Sync(laptopState, server.Exchange); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := desktop.Sync(state, server.Exchange); err == nil {
+		t.Fatal("sync succeeded although a hook vetoed the pulled change")
+	}
+	if task, err := desktop.GetTask(1); err != nil || task.Status != entity.TaskStatusToDo {
+		t.Errorf("task after a vetoed sync = %+v, %v; want it unchanged", task, err)
+	}
+}
@@ -1,7 +1,11 @@
 package usecase
 
 import (
+	"cmp"
 	"fmt"
+	"iter"
+	"slices"
+	"time"
 
 	"github.com/Illuminateee/task-tracker.git/entity"
 	"github.com/Illuminateee/task-tracker.git/repository"
@@ -9,7 +13,10 @@ import (
 
 // TaskUseCase handles task business logic
 type TaskUseCase struct {
-	taskRepo repository.TaskRepository
+	taskRepo  repository.TaskRepository
+	hooks     []TaskHook
+	journals  []TaskJournal
+	listeners []TaskListener
 }
 
 // NewTaskUseCase creates a new task use case
@@ -22,20 +29,21 @@ func NewTaskUseCase(taskRepo repository.TaskRepository) *TaskUseCase {
 // CreateTask creates a new task
 func (uc *TaskUseCase) CreateTask(title, description string) (*entity.Task, error) {
 	if title == "" {
-		return nil, fmt.Errorf("task title cannot be empty")
+		return nil, fmt.Errorf("%w: task title cannot be empty", entity.ErrInvalidInput)
 	}
 
-	id, err := uc.taskRepo.GetNextID()
+	// Another task taking the ID first makes the change run again with the next one
+	events, err := uc.change(true, func() ([]entity.TaskEvent, error) {
+		id, err := uc.taskRepo.GetNextID()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next ID: %w", err)
+		}
+		return []entity.TaskEvent{{Type: entity.TaskAdded, After: entity.NewTask(id, title, description)}}, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get next ID: %w", err)
-	}
-
-	task := entity.NewTask(id, title, description)
-	if err := uc.taskRepo.Create(task); err != nil {
 		return nil, fmt.Errorf("failed to create task: %w", err)
 	}
-
-	return task, nil
+	return events[0].After, nil
 }
 
 // GetTask retrieves a task by ID
@@ -56,6 +64,28 @@ func (uc *TaskUseCase) GetAllTasks() ([]*entity.Task, error) {
 	return tasks, nil
 }
 
+// IterateTasks iterates over all tasks in ID order without loading them all at once
+func (uc *TaskUseCase) IterateTasks() iter.Seq2[*entity.Task, error] {
+	return uc.taskRepo.All()
+}
+
+// ListTasksPage retrieves one page of tasks with the given statuses
+func (uc *TaskUseCase) ListTasksPage(statuses []entity.TaskStatus, offset, limit int) (*repository.TaskPage, error) {
+	if offset < 0 || limit < 0 {
+		return nil, fmt.Errorf("%w: offset and limit cannot be negative", entity.ErrInvalidInput)
+	}
+
+	page, err := uc.taskRepo.List(repository.ListOptions{
+		Statuses: statuses,
+		Offset:   offset,
+		Limit:    limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	return page, nil
+}
+
 // GetTasksByStatus retrieves tasks filtered by status
 func (uc *TaskUseCase) GetTasksByStatus(status entity.TaskStatus) ([]*entity.Task, error) {
 	tasks, err := uc.taskRepo.GetByStatus(status)
@@ -65,61 +95,171 @@ func (uc *TaskUseCase) GetTasksByStatus(status entity.TaskStatus) ([]*entity.Tas
 	return tasks, nil
 }
 
-// GetPendingTasks retrieves all non-done tasks (todo + in-progress)
-func (uc *TaskUseCase) GetPendingTasks() ([]*entity.Task, error) {
-	todoTasks, err := uc.taskRepo.GetByStatus(entity.TaskStatusToDo)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get todo tasks: %w", err)
+// GetTasksDueBetween retrieves the unfinished tasks due in [from, to), sorted by due date
+func (uc *TaskUseCase) GetTasksDueBetween(from, to time.Time) ([]*entity.Task, error) {
+	var tasks []*entity.Task
+	for task, err := range uc.taskRepo.All() {
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tasks: %w", err)
+		}
+		if task.Due != nil && task.Status != entity.TaskStatusDone && !task.Due.Before(from) && task.Due.Before(to) {
+			tasks = append(tasks, task)
+		}
 	}
+	slices.SortStableFunc(tasks, func(a, b *entity.Task) int {
+		return cmp.Or(a.Due.Compare(*b.Due), cmp.Compare(a.ID, b.ID))
+	})
+	return tasks, nil
+}
 
-	inProgressTasks, err := uc.taskRepo.GetByStatus(entity.TaskStatusInProgress)
+// GetPendingTasks retrieves all non-done tasks (todo + in-progress) sorted by ID
+func (uc *TaskUseCase) GetPendingTasks() ([]*entity.Task, error) {
+	page, err := uc.taskRepo.List(repository.ListOptions{
+		Statuses: []entity.TaskStatus{entity.TaskStatusToDo, entity.TaskStatusInProgress},
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get in-progress tasks: %w", err)
+		return nil, fmt.Errorf("failed to get pending tasks: %w", err)
 	}
-
-	// Combine and sort
-	allPending := append(todoTasks, inProgressTasks...)
-	return allPending, nil
+	return page.Tasks, nil
 }
 
 // UpdateTask updates an existing task
 func (uc *TaskUseCase) UpdateTask(id int, title, description string) (*entity.Task, error) {
-	task, err := uc.taskRepo.GetByID(id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get task for update: %w", err)
-	}
+	return uc.UpdateTaskAtVersion(id, entity.AnyVersion, title, description)
+}
+
+// UpdateTaskAtVersion updates an existing task, failing with a conflict when
+// the stored task has a different version, unless version is entity.AnyVersion
+func (uc *TaskUseCase) UpdateTaskAtVersion(id, version int, title, description string) (*entity.Task, error) {
+	events, err := uc.change(version == entity.AnyVersion, func() ([]entity.TaskEvent, error) {
+		task, err := uc.taskRepo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		if err := expectVersion(task, version); err != nil {
+			return nil, err
+		}
 
-	task.Update(title, description)
-	if err := uc.taskRepo.Update(task); err != nil {
+		before := task.Clone()
+		task.Update(title, description)
+		return []entity.TaskEvent{{Type: entity.TaskModified, Before: before, After: task}}, nil
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to update task: %w", err)
 	}
-
-	return task, nil
+	return events[0].After, nil
 }
 
 // UpdateTaskStatus updates the status of a task
 func (uc *TaskUseCase) UpdateTaskStatus(id int, status entity.TaskStatus) (*entity.Task, error) {
-	task, err := uc.taskRepo.GetByID(id)
+	return uc.UpdateTaskStatusAtVersion(id, entity.AnyVersion, status)
+}
+
+// UpdateTaskStatusAtVersion updates the status of a task, failing with a
+// conflict when the stored task has a different version, unless version is
+// entity.AnyVersion
+func (uc *TaskUseCase) UpdateTaskStatusAtVersion(id, version int, status entity.TaskStatus) (*entity.Task, error) {
+	events, err := uc.change(version == entity.AnyVersion, func() ([]entity.TaskEvent, error) {
+		task, err := uc.taskRepo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		if err := expectVersion(task, version); err != nil {
+			return nil, err
+		}
+
+		before := task.Clone()
+		task.UpdateStatus(status)
+		return []entity.TaskEvent{{Type: entity.TaskStatusChanged, Before: before, After: task}}, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get task for status update: %w", err)
+		return nil, fmt.Errorf("failed to update task status: %w", err)
 	}
+	return events[0].After, nil
+}
+
+// SetTaskDue sets the due date of a task, or clears it when due is nil
+func (uc *TaskUseCase) SetTaskDue(id int, due *time.Time) (*entity.Task, error) {
+	events, err := uc.change(true, func() ([]entity.TaskEvent, error) {
+		task, err := uc.taskRepo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		before := task.Clone()
+		task.SetDue(due)
+		return []entity.TaskEvent{{Type: entity.TaskModified, Before: before, After: task}}, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update task due date: %w", err)
+	}
+	return events[0].After, nil
+}
+
+// expectVersion checks a caller-supplied version; entity.AnyVersion accepts any
+func expectVersion(task *entity.Task, version int) error {
+	if version != entity.AnyVersion && task.Version != version {
+		return entity.NewVersionConflictError(task.ID, task.Version, version)
+	}
+	return nil
+}
+
+// UpdateTasksStatus updates the status of several tasks in a single write
+func (uc *TaskUseCase) UpdateTasksStatus(ids []int, status entity.TaskStatus) ([]*entity.Task, error) {
+	events, err := uc.change(true, func() ([]entity.TaskEvent, error) {
+		events := make([]entity.TaskEvent, 0, len(ids))
+		for _, id := range ids {
+			task, err := uc.taskRepo.GetByID(id)
+			if err != nil {
+				return nil, err
+			}
 
-	task.UpdateStatus(status)
-	if err := uc.taskRepo.Update(task); err != nil {
+			before := task.Clone()
+			task.UpdateStatus(status)
+			events = append(events, entity.TaskEvent{Type: entity.TaskStatusChanged, Before: before, After: task})
+		}
+		return events, nil
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to update task status: %w", err)
 	}
 
-	return task, nil
+	tasks := make([]*entity.Task, len(events))
+	for i, event := range events {
+		tasks[i] = event.After
+	}
+	return tasks, nil
+}
+
+// DeleteTasks deletes several tasks in a single write
+func (uc *TaskUseCase) DeleteTasks(ids []int) error {
+	if _, err := uc.change(true, func() ([]entity.TaskEvent, error) { return uc.deleteEvents(ids) }); err != nil {
+		return fmt.Errorf("failed to delete tasks: %w", err)
+	}
+	return nil
 }
 
 // DeleteTask deletes a task by ID
 func (uc *TaskUseCase) DeleteTask(id int) error {
-	if err := uc.taskRepo.Delete(id); err != nil {
+	if _, err := uc.change(true, func() ([]entity.TaskEvent, error) { return uc.deleteEvents([]int{id}) }); err != nil {
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
 	return nil
 }
 
+// deleteEvents describes the deletion of the tasks with the given IDs
+func (uc *TaskUseCase) deleteEvents(ids []int) ([]entity.TaskEvent, error) {
+	events := make([]entity.TaskEvent, 0, len(ids))
+	for _, id := range ids {
+		task, err := uc.taskRepo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, entity.TaskEvent{Type: entity.TaskDeleted, Before: task})
+	}
+	return events, nil
+}
+
 // MarkTaskDone marks a task as done
 func (uc *TaskUseCase) MarkTaskDone(id int) (*entity.Task, error) {
 	return uc.UpdateTaskStatus(id, entity.TaskStatusDone)
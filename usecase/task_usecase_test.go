@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+	"github.com/Illuminateee/task-tracker.git/repository"
+)
+
+// legacyTasks is a tasks file written before versions were tracked
+const legacyTasks = `[
+  {
+    "id": 1,
+    "title": "Buy groceries",
+    "description": "Milk, bread, eggs",
+    "status": "todo",
+    "created_at": "2025-10-06T15:23:47.8920386+07:00",
+    "updated_at": "2025-10-06T15:23:47.8920386+07:00"
+  }
+]`
+
+// newLegacyUseCase returns a use case over a copy of legacyTasks
+func newLegacyUseCase(t *testing.T) *TaskUseCase {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	if err := os.WriteFile(path, []byte(legacyTasks), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return NewTaskUseCase(repository.NewFileTaskRepository(path, repository.FileOptions{}))
+}
+
+func TestLegacyTaskStartsAtVersionOne(t *testing.T) {
+	uc := newLegacyUseCase(t)
+
+	task, err := uc.GetTask(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Version != 1 {
+		t.Errorf("legacy task version = %d, want 1", task.Version)
+	}
+
+	// A client that never saw a version cannot claim to hold the current one
+	if _, err := uc.UpdateTaskAtVersion(1, 0, "Stale edit", ""); !errors.Is(err, entity.ErrConflict) {
+		t.Errorf("update at version 0 = %v, want a conflict", err)
+	}
+}
+
+func TestStaleVersionIsRejected(t *testing.T) {
+	uc := newLegacyUseCase(t)
+
+	updated, err := uc.UpdateTaskAtVersion(1, 1, "First edit", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Version != 2 {
+		t.Errorf("version after update = %d, want 2", updated.Version)
+	}
+
+	if _, err := uc.UpdateTaskAtVersion(1, 1, "Second edit", ""); !errors.Is(err, entity.ErrConflict) {
+		t.Errorf("update at stale version = %v, want a conflict", err)
+	}
+	if _, err := uc.UpdateTaskStatusAtVersion(1, 1, entity.TaskStatusDone); !errors.Is(err, entity.ErrConflict) {
+		t.Errorf("status change at stale version = %v, want a conflict", err)
+	}
+
+	task, err := uc.GetTask(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Title != "First edit" || task.Status != entity.TaskStatusToDo {
+		t.Errorf("task = %q (%s), want the first edit only", task.Title, task.Status)
+	}
+
+	// Callers that do not track versions still apply their change
+	if _, err := uc.UpdateTaskStatusAtVersion(1, entity.AnyVersion, entity.TaskStatusDone); err != nil {
+		t.Errorf("status change at any version = %v, want success", err)
+	}
+}
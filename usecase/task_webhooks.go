@@ -0,0 +1,366 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+	"github.com/Illuminateee/task-tracker.git/repository"
+)
+
+// Webhook retry schedule: the delay doubles from webhookFirstRetry up to
+// webhookMaxRetry until webhookMaxAttempts deliveries have failed
+const (
+	webhookMaxAttempts = 12
+	webhookFirstRetry  = 10 * time.Second
+	webhookMaxRetry    = time.Hour
+)
+
+// A flush claims up to webhookClaimBatch entries at a time for
+// webhookClaimDuration, long enough to post them all even when each post
+// runs into its timeout
+const (
+	webhookClaimBatch    = 5
+	webhookClaimDuration = 2 * time.Minute
+)
+
+// errNothingDue ends an outbox update that found nothing to deliver without saving it
+var errNothingDue = errors.New("no webhook deliveries due")
+
+// WebhookPayload is the JSON body posted to webhooks for every task change
+type WebhookPayload struct {
+	ID         string               `json:"id"`
+	Event      entity.TaskEventType `json:"event"`
+	OccurredAt time.Time            `json:"occurred_at"`
+	Before     *entity.Task         `json:"before"`
+	After      *entity.Task         `json:"after"`
+}
+
+// WebhookPost delivers an outbox entry to its URL; signing and transport are up to the caller
+type WebhookPost func(entry *entity.OutboxEntry) error
+
+// WebhookFlushResult counts the outcome of delivering the outbox
+type WebhookFlushResult struct {
+	Delivered int
+	// Retrying counts failed deliveries that will be tried again
+	Retrying int
+	// Failed counts deliveries that used up their retries
+	Failed int
+}
+
+// WebhookOutbox queues task changes for webhooks in a persistent outbox and
+// delivers them, retrying with exponential backoff, so that events survive
+// the process exiting or the receiver being down
+type WebhookOutbox struct {
+	store   repository.OutboxStore
+	urls    []string
+	post    WebhookPost
+	onError func(error)
+
+	// mu guards the state of the background delivery
+	mu sync.Mutex
+	// delivering is set while the background delivery runs; again asks it
+	// for another round because a change arrived meanwhile
+	delivering bool
+	again      bool
+	deliveries sync.WaitGroup
+	// ready holds the entries this process queued for changes it has since
+	// stored; other processes leave them alone while they are held
+	ready map[[2]string]bool
+}
+
+// NewWebhookOutbox creates an outbox delivering to urls; onError receives
+// failures of the background delivery
+func NewWebhookOutbox(store repository.OutboxStore, urls []string, post WebhookPost, onError func(error)) *WebhookOutbox {
+	return &WebhookOutbox{
+		store:   store,
+		urls:    urls,
+		post:    post,
+		onError: onError,
+		ready:   make(map[[2]string]bool),
+	}
+}
+
+// Record implements TaskJournal: the changes are queued for every URL before
+// they are stored, so that none is lost if the process stops right after
+// storing them. The entries are held from other processes until this one
+// stores the changes and delivers them in the background, so a slow receiver
+// never holds up a change; they are dropped if storing fails. A process that
+// stops in between leaves them to be delivered once the hold expires
+func (o *WebhookOutbox) Record(events []entity.TaskEvent) (func(stored bool), error) {
+	keys, err := o.enqueue(events, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return func(stored bool) {
+		if !stored {
+			if err := o.discard(keys); err != nil {
+				o.onError(err)
+			}
+			return
+		}
+
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		for _, key := range keys {
+			o.ready[key] = true
+		}
+		if o.delivering {
+			o.again = true
+			return
+		}
+		o.delivering = true
+		o.deliveries.Add(1)
+		go o.deliver()
+	}, nil
+}
+
+// deliver flushes the outbox until no change arrived during the last flush;
+// at most one runs at a time
+func (o *WebhookOutbox) deliver() {
+	defer o.deliveries.Done()
+	for {
+		if _, err := o.Flush(false); err != nil {
+			o.onError(err)
+		}
+
+		o.mu.Lock()
+		if !o.again {
+			o.delivering = false
+			o.mu.Unlock()
+			return
+		}
+		o.again = false
+		o.mu.Unlock()
+	}
+}
+
+// Wait waits up to timeout for the background delivery to finish and reports
+// whether it did; deliveries cut short are retried by a later flush
+func (o *WebhookOutbox) Wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		o.deliveries.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// enqueue adds an entry per URL for each event to the stored outbox, held
+// for webhookClaimDuration, and returns their keys
+func (o *WebhookOutbox) enqueue(events []entity.TaskEvent, now time.Time) ([][2]string, error) {
+	var entries []*entity.OutboxEntry
+	for _, event := range events {
+		id := rand.Text()
+		payload, err := json.Marshal(WebhookPayload{
+			ID:         id,
+			Event:      event.Type,
+			OccurredAt: now,
+			Before:     event.Before,
+			After:      event.After,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode webhook payload: %w", err)
+		}
+		for _, url := range o.urls {
+			entries = append(entries, &entity.OutboxEntry{
+				ID:           id,
+				URL:          url,
+				Event:        event.Type,
+				Payload:      payload,
+				CreatedAt:    now,
+				NextAttempt:  now,
+				ClaimedUntil: now.Add(webhookClaimDuration),
+			})
+		}
+	}
+
+	err := o.store.Update(func(outbox *entity.Outbox) error {
+		outbox.Entries = append(outbox.Entries, entries...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue webhook delivery: %w", err)
+	}
+
+	keys := make([][2]string, len(entries))
+	for i, entry := range entries {
+		keys[i] = [2]string{entry.ID, entry.URL}
+	}
+	return keys, nil
+}
+
+// discard removes the entries with the given keys from the stored outbox
+func (o *WebhookOutbox) discard(keys [][2]string) error {
+	err := o.store.Update(func(outbox *entity.Outbox) error {
+		outbox.Entries = slices.DeleteFunc(outbox.Entries, func(entry *entity.OutboxEntry) bool {
+			return slices.Contains(keys, [2]string{entry.ID, entry.URL})
+		})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to drop webhook deliveries of a change that was not stored: %w", err)
+	}
+	return nil
+}
+
+// Flush delivers the entries whose next attempt is due, or every entry
+// including failed ones when all is set. Entries being delivered by another
+// process are skipped. The returned error joins the delivery failures
+func (o *WebhookOutbox) Flush(all bool) (*WebhookFlushResult, error) {
+	result := &WebhookFlushResult{}
+	var errs []error
+	attempted := make(map[[2]string]bool)
+	for {
+		batch, err := o.claim(all, attempted)
+		if err != nil {
+			return result, err
+		}
+		if len(batch) == 0 {
+			return result, errors.Join(errs...)
+		}
+
+		// Post without holding the lock, so that changes can be queued meanwhile
+		delivered := make(map[[2]string]bool)
+		for _, entry := range batch {
+			key := [2]string{entry.ID, entry.URL}
+			attempted[key] = true
+			entry.ClaimedUntil = time.Time{}
+			if err := o.post(entry); err != nil {
+				entry.Attempts++
+				entry.LastError = err.Error()
+				if entry.Attempts >= webhookMaxAttempts {
+					entry.Failed = true
+					result.Failed++
+					errs = append(errs, fmt.Errorf("webhook delivery of %s event to %s failed %d times, giving up: %w", entry.Event, entry.URL, entry.Attempts, err))
+				} else {
+					entry.NextAttempt = time.Now().Add(webhookBackoff(entry.Attempts))
+					result.Retrying++
+					errs = append(errs, fmt.Errorf("webhook delivery of %s event to %s failed, retrying at %s: %w", entry.Event, entry.URL, entry.NextAttempt.Format(time.RFC3339), err))
+				}
+				continue
+			}
+			result.Delivered++
+			delivered[key] = true
+		}
+
+		if err := o.settle(batch, delivered); err != nil {
+			return result, err
+		}
+	}
+}
+
+// claim marks up to webhookClaimBatch due entries not in attempted as being
+// delivered by this process and returns copies of them
+func (o *WebhookOutbox) claim(all bool, attempted map[[2]string]bool) ([]*entity.OutboxEntry, error) {
+	var batch []*entity.OutboxEntry
+	err := o.store.Update(func(outbox *entity.Outbox) error {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+
+		now := time.Now()
+		for _, entry := range outbox.Entries {
+			if len(batch) == webhookClaimBatch {
+				break
+			}
+			// Held entries are skipped unless this process holds them
+			key := [2]string{entry.ID, entry.URL}
+			if attempted[key] || entry.ClaimedUntil.After(now) && !o.ready[key] {
+				continue
+			}
+			if all || (!entry.Failed && !entry.NextAttempt.After(now)) {
+				delete(o.ready, key)
+				entry.ClaimedUntil = now.Add(webhookClaimDuration)
+				claimed := *entry
+				batch = append(batch, &claimed)
+			}
+		}
+		if len(batch) == 0 {
+			return errNothingDue
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errNothingDue) {
+		return nil, fmt.Errorf("failed to update webhook outbox: %w", err)
+	}
+	return batch, nil
+}
+
+// settle applies the outcome of delivering batch to the stored outbox,
+// removing the delivered entries
+func (o *WebhookOutbox) settle(batch []*entity.OutboxEntry, delivered map[[2]string]bool) error {
+	outcomes := make(map[[2]string]*entity.OutboxEntry, len(batch))
+	for _, entry := range batch {
+		outcomes[[2]string{entry.ID, entry.URL}] = entry
+	}
+
+	err := o.store.Update(func(outbox *entity.Outbox) error {
+		remaining := outbox.Entries[:0]
+		for _, entry := range outbox.Entries {
+			key := [2]string{entry.ID, entry.URL}
+			outcome, ok := outcomes[key]
+			switch {
+			case !ok:
+				remaining = append(remaining, entry)
+			case !delivered[key]:
+				remaining = append(remaining, outcome)
+			}
+		}
+		outbox.Entries = remaining
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update webhook outbox: %w", err)
+	}
+	return nil
+}
+
+// Entries returns the deliveries waiting in the outbox
+func (o *WebhookOutbox) Entries() ([]*entity.OutboxEntry, error) {
+	outbox, err := o.store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load webhook outbox: %w", err)
+	}
+	return outbox.Entries, nil
+}
+
+// DropFailed removes the deliveries that used up their retries
+func (o *WebhookOutbox) DropFailed() (int, error) {
+	dropped := 0
+	err := o.store.Update(func(outbox *entity.Outbox) error {
+		remaining := outbox.Entries[:0]
+		for _, entry := range outbox.Entries {
+			if !entry.Failed {
+				remaining = append(remaining, entry)
+			}
+		}
+		dropped = len(outbox.Entries) - len(remaining)
+		outbox.Entries = remaining
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to update webhook outbox: %w", err)
+	}
+	return dropped, nil
+}
+
+// webhookBackoff returns the delay before retrying a delivery that failed attempts times
+func webhookBackoff(attempts int) time.Duration {
+	delay := webhookFirstRetry
+	for i := 1; i < attempts && delay < webhookMaxRetry; i++ {
+		delay *= 2
+	}
+	return min(delay, webhookMaxRetry)
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Illuminateee/task-tracker.git/entity"
+	"github.com/Illuminateee/task-tracker.git/repository"
+)
+
+// journalFunc adapts a function to TaskJournal
+type journalFunc func(events []entity.TaskEvent) (func(stored bool), error)
+
+func (f journalFunc) Record(events []entity.TaskEvent) (func(stored bool), error) {
+	return f(events)
+}
+
+// postRecorder records the entries posted to it
+type postRecorder struct {
+	mu     sync.Mutex
+	posted []*entity.OutboxEntry
+}
+
+func (p *postRecorder) post(entry *entity.OutboxEntry) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.posted = append(p.posted, entry)
+	return nil
+}
+
+func (p *postRecorder) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.posted)
+}
+
+// newWebhookUseCase returns a use case over the tasks file at path whose
+// changes are queued in its outbox and posted to recorder
+func newWebhookUseCase(t *testing.T, path string, recorder *postRecorder) (*TaskUseCase, *WebhookOutbox) {
+	t.Helper()
+	repo := repository.NewFileTaskRepository(path, repository.FileOptions{})
+	outbox := NewWebhookOutbox(repository.NewFileOutboxStore(path+".outbox", repo), []string{"https://example.com/hook"}, recorder.post, func(err error) {
+		t.Errorf("unexpected webhook error: %v", err)
+	})
+	uc := NewTaskUseCase(repo)
+	uc.AddJournal(outbox)
+	return uc, outbox
+}
+
+func TestWebhookEntryQueuedBeforeTheChangeIsStored(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	recorder := &postRecorder{}
+	uc, outbox := newWebhookUseCase(t, path, recorder)
+
+	// Another process sharing the outbox
+	otherRecorder := &postRecorder{}
+	_, other := newWebhookUseCase(t, path, otherRecorder)
+
+	uc.AddJournal(journalFunc(func(events []entity.TaskEvent) (func(bool), error) {
+		if events[0].Type != entity.TaskAdded {
+			return func(bool) {}, nil
+		}
+		entries, err := outbox.Entries()
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) != 1 {
+			t.Errorf("%d entries queued before the task is stored, want 1", len(entries))
+		}
+		if _, err := uc.taskRepo.GetByID(1); !errors.Is(err, entity.ErrTaskNotFound) {
+			t.Errorf("task stored before its webhook entry was queued: %v", err)
+		}
+
+		// The entry is held until this process has stored the change
+		if result, err := other.Flush(true); err != nil || result.Delivered != 0 {
+			t.Errorf("other process delivered %+v, %v before the change was stored", result, err)
+		}
+		return func(bool) {}, nil
+	}))
+
+	if _, err := uc.CreateTask("Write tests", ""); err != nil {
+		t.Fatal(err)
+	}
+	task, err := uc.UpdateTask(1, "Write more tests", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !outbox.Wait(5 * time.Second) {
+		t.Fatal("delivery did not finish")
+	}
+	if recorder.count() != 2 || otherRecorder.count() != 0 {
+		t.Fatalf("posted %d here and %d by the other process, want 2 and 0", recorder.count(), otherRecorder.count())
+	}
+
+	var payload WebhookPayload
+	if err := json.Unmarshal(recorder.posted[1].Payload, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.After.Version != task.Version || payload.Before.Version != task.Version-1 {
+		t.Errorf("payload versions %d -> %d, want %d -> %d as stored", payload.Before.Version, payload.After.Version, task.Version-1, task.Version)
+	}
+}
+
+func TestWebhookEntryDroppedWhenTheChangeIsNotStored(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	recorder := &postRecorder{}
+	uc, outbox := newWebhookUseCase(t, path, recorder)
+	if _, err := uc.CreateTask("Write tests", ""); err != nil {
+		t.Fatal(err)
+	}
+	if !outbox.Wait(5 * time.Second) {
+		t.Fatal("delivery did not finish")
+	}
+
+	// Another process changes the task while the hooks run
+	other := NewTaskUseCase(repository.NewFileTaskRepository(path, repository.FileOptions{}))
+	uc.AddHook(hookFunc(func(event entity.TaskEvent) (*entity.Task, error) {
+		_, err := other.UpdateTaskStatus(1, entity.TaskStatusDone)
+		return nil, err
+	}))
+	if _, err := uc.UpdateTaskAtVersion(1, 1, "Raced", ""); !errors.Is(err, entity.ErrConflict) {
+		t.Fatalf("update at a raced version = %v, want a conflict", err)
+	}
+
+	entries, err := outbox.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("%d entries left for a change that was not stored", len(entries))
+	}
+	if recorder.count() != 1 {
+		t.Errorf("posted %d deliveries, want only the one for the stored change", recorder.count())
+	}
+}
+
+func TestWebhookEntriesForPulledChanges(t *testing.T) {
+	dir := t.TempDir()
+	server := NewSyncServer(repository.NewFileSyncStore(filepath.Join(dir, "sync-store.json")))
+
+	laptop := NewTaskUseCase(repository.NewFileTaskRepository(filepath.Join(dir, "laptop.json"), repository.FileOptions{}))
+	if _, err := laptop.CreateTask("Pulled", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := laptop.Sync(&entity.SyncState{Fields: make(map[int]map[string]string)}, server.Exchange); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := &postRecorder{}
+	desktop, outbox := newWebhookUseCase(t, filepath.Join(dir, "desktop.json"), recorder)
+	if _, err := desktop.Sync(&entity.SyncState{Fields: make(map[int]map[string]string)}, server.Exchange); err != nil {
+		t.Fatal(err)
+	}
+	if !outbox.Wait(5 * time.Second) {
+		t.Fatal("delivery did not finish")
+	}
+	if recorder.count() != 1 || recorder.posted[0].Event != entity.TaskAdded {
+		t.Fatalf("posted %d deliveries, want the pulled task added", recorder.count())
+	}
+}